@@ -0,0 +1,252 @@
+package e2e
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxCapture is the capture limit used when Config.MaxCaptureSize is zero.
+const defaultMaxCapture = 10 << 20
+
+// bodyStats describes a response body that has been read to the end.
+type bodyStats struct {
+	length    int64
+	sums      map[crypto.Hash][]byte
+	truncated bool
+}
+
+// byteRange is a requested Range; end is negative for an open range.
+type byteRange struct {
+	start, end int64
+}
+
+// MaxCapture sets how many bytes of the response body are kept in memory,
+// overriding Config.MaxCaptureSize. The rest is streamed through to compute
+// the length and checksums without being stored.
+func (h *HTTPBuilder) MaxCapture(size int64) *HTTPBuilder {
+	h.maxCapture = size
+
+	return h
+}
+
+// Checksum computes hash over the response body so ExpectChecksum can
+// check it. SHA-256 is always computed. Other hashes must be linked into
+// the binary, for example by importing crypto/sha512.
+func (h *HTTPBuilder) Checksum(hash crypto.Hash) *HTTPBuilder {
+	if !hash.Available() {
+		h.suite.t.Fatalf("Checksum %v is not available; import its package", hash)
+	}
+
+	h.checksums = append(h.checksums, hash)
+
+	return h
+}
+
+// Range requests the bytes from start to end inclusive. A negative end
+// requests everything from start.
+func (h *HTTPBuilder) Range(start, end int64) *HTTPBuilder {
+	h.byteRange = &byteRange{start: start, end: end}
+
+	if end < 0 {
+		return h.Header("Range", fmt.Sprintf("bytes=%d-", start))
+	}
+
+	return h.Header("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+}
+
+// ExpectBodyLength validates the number of bytes in the response body.
+// The body is streamed, so any size can be checked.
+func (h *HTTPBuilder) ExpectBodyLength(length int64) *HTTPBuilder {
+	return h.expect(func() error {
+		h.readResponseBody()
+
+		if h.bodyStats.length == length {
+			return nil
+		}
+
+		return errors.New(h.formatError("Body length mismatch", strconv.FormatInt(length, 10), strconv.FormatInt(h.bodyStats.length, 10)))
+	})
+}
+
+// ExpectSHA256 validates the hex-encoded SHA-256 of the response body.
+func (h *HTTPBuilder) ExpectSHA256(sum string) *HTTPBuilder {
+	return h.ExpectChecksum(crypto.SHA256, sum)
+}
+
+// ExpectChecksum validates the hex-encoded hash of the response body. Hashes
+// other than SHA-256 must be requested with Checksum before Execute.
+func (h *HTTPBuilder) ExpectChecksum(hash crypto.Hash, sum string) *HTTPBuilder {
+	return h.expect(func() error {
+		h.readResponseBody()
+
+		actual, ok := h.bodyStats.sums[hash]
+		if !ok {
+			return fmt.Errorf("checksum %v was not computed; call Checksum(%v) before Execute", hash, hash)
+		}
+
+		if strings.EqualFold(hex.EncodeToString(actual), sum) {
+			return nil
+		}
+
+		assertion := fmt.Sprintf("Checksum mismatch (%v)", hash)
+
+		return errors.New(h.formatError(assertion, strings.ToLower(sum), hex.EncodeToString(actual)))
+	})
+}
+
+// ExpectRange validates the response to a Range request: a 206 status, a
+// Content-Range matching the requested range and a body of that length.
+func (h *HTTPBuilder) ExpectRange() *HTTPBuilder {
+	if h.byteRange == nil {
+		h.suite.t.Fatal("ExpectRange requires Range to be set before Execute")
+	}
+
+	return h.expect(func() error {
+		h.readResponseBody()
+
+		if h.resp.StatusCode != http.StatusPartialContent {
+			expected := fmt.Sprintf("%d %s", http.StatusPartialContent, http.StatusText(http.StatusPartialContent))
+			actual := fmt.Sprintf("%d %s", h.resp.StatusCode, http.StatusText(h.resp.StatusCode))
+
+			return errors.New(h.formatError("Range status mismatch", expected, actual))
+		}
+
+		contentRange := h.resp.Header.Get("Content-Range")
+
+		start, end, err := parseContentRange(contentRange)
+		if err != nil || start != h.byteRange.start || (h.byteRange.end >= 0 && end != h.byteRange.end) {
+			return errors.New(h.formatError("Content-Range mismatch", h.byteRange.String(), contentRange))
+		}
+
+		if length := end - start + 1; h.bodyStats.length != length {
+			return errors.New(h.formatError("Range length mismatch", strconv.FormatInt(length, 10), strconv.FormatInt(h.bodyStats.length, 10)))
+		}
+
+		return nil
+	})
+}
+
+// String formats the range as expected in Content-Range.
+func (r *byteRange) String() string {
+	if r.end < 0 {
+		return fmt.Sprintf("bytes %d-*/*", r.start)
+	}
+
+	return fmt.Sprintf("bytes %d-%d/*", r.start, r.end)
+}
+
+// parseContentRange parses "bytes start-end/total".
+func parseContentRange(value string) (int64, int64, error) {
+	spec, found := strings.CutPrefix(value, "bytes ")
+	if !found {
+		return 0, 0, fmt.Errorf("unsupported Content-Range %q", value)
+	}
+
+	span, _, _ := strings.Cut(spec, "/")
+	first, last, _ := strings.Cut(span, "-")
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range start: %w", err)
+	}
+
+	end, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range end: %w", err)
+	}
+
+	return start, end, nil
+}
+
+// captureLimit returns the number of body bytes kept in memory.
+func (h *HTTPBuilder) captureLimit() int64 {
+	if h.maxCapture > 0 {
+		return h.maxCapture
+	}
+
+	if h.suite.config.MaxCaptureSize > 0 {
+		return h.suite.config.MaxCaptureSize
+	}
+
+	return defaultMaxCapture
+}
+
+// completeBody returns the response body, failing when it was larger than
+// the capture limit and only its head is available.
+func (h *HTTPBuilder) completeBody() ([]byte, error) {
+	body := h.readResponseBody()
+
+	if h.bodyStats.truncated {
+		return nil, fmt.Errorf("response body of %d bytes exceeds the capture limit of %d bytes; raise MaxCapture to assert on its content", h.bodyStats.length, h.captureLimit())
+	}
+
+	return body, nil
+}
+
+// captureBody streams r to the end, keeping at most limit bytes and
+// computing the length and checksums.
+func captureBody(r io.Reader, limit int64, checksums []crypto.Hash) ([]byte, *bodyStats, error) {
+	hashes := map[crypto.Hash]hash.Hash{crypto.SHA256: sha256.New()}
+
+	for _, h := range checksums {
+		if _, ok := hashes[h]; !ok {
+			hashes[h] = h.New()
+		}
+	}
+
+	writers := make([]io.Writer, 0, len(hashes)+1)
+	for _, h := range hashes {
+		writers = append(writers, h)
+	}
+
+	capture := &captureWriter{limit: limit}
+	writers = append(writers, capture)
+
+	length, err := io.Copy(io.MultiWriter(writers...), r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	stats := &bodyStats{
+		length:    length,
+		sums:      make(map[crypto.Hash][]byte, len(hashes)),
+		truncated: capture.truncated,
+	}
+
+	for h, sum := range hashes {
+		stats.sums[h] = sum.Sum(nil)
+	}
+
+	return capture.data, stats, nil
+}
+
+// captureWriter keeps the first limit bytes written to it.
+type captureWriter struct {
+	data      []byte
+	limit     int64
+	written   int64
+	truncated bool
+}
+
+func (w *captureWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+
+	if room := w.limit - int64(len(w.data)); room < int64(len(p)) {
+		w.data = append(w.data, p[:max(room, 0)]...)
+		w.truncated = true
+
+		return len(p), nil
+	}
+
+	w.data = append(w.data, p...)
+
+	return len(p), nil
+}
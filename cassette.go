@@ -0,0 +1,556 @@
+package e2e
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// CassetteMode selects whether a cassette records, replays or is bypassed.
+type CassetteMode string
+
+const (
+	// CassetteReplay serves every request from the cassette file and fails
+	// requests that were not recorded. This is the default.
+	CassetteReplay CassetteMode = "replay"
+	// CassetteRecord sends requests to the server and saves the exchanges
+	// to the cassette file when the test ends, replacing its contents.
+	// Response bodies are saved up to Config.MaxCaptureSize; replaying one
+	// that was cut off fails.
+	CassetteRecord CassetteMode = "record"
+	// CassettePassthrough sends requests to the server without recording.
+	CassettePassthrough CassetteMode = "passthrough"
+)
+
+// MatchOn names a part of the request compared when replaying.
+type MatchOn string
+
+const (
+	// MatchMethod compares request methods.
+	MatchMethod MatchOn = "method"
+	// MatchPath compares URL paths. Hosts are never compared, so cassettes
+	// recorded against one address replay against any other.
+	MatchPath MatchOn = "path"
+	// MatchQuery compares query parameters regardless of their order.
+	MatchQuery MatchOn = "query"
+	// MatchBody compares request bodies; JSON bodies are compared by value.
+	// Only the first Config.MaxCaptureSize bytes are recorded and compared.
+	MatchBody MatchOn = "body"
+)
+
+// cassetteModeEnv overrides CassetteConfig.Mode, as in
+// E2E_CASSETTE_MODE=record go test ./...
+const cassetteModeEnv = "E2E_CASSETTE_MODE"
+
+// defaultCassetteDir holds cassettes when CassetteConfig.Dir is empty.
+const defaultCassetteDir = "testdata/cassettes"
+
+// CassetteConfig records HTTP exchanges to a file and replays them, so
+// tests can run without the services they depend on. Only requests sent
+// with HTTPBuilder go through the cassette.
+type CassetteConfig struct {
+	// Dir holds the cassette files. Defaults to testdata/cassettes.
+	Dir string
+	// Name is the file name without extension. Defaults to the test name.
+	Name string
+	// Mode is used when the E2E_CASSETTE_MODE environment variable is not
+	// set. Defaults to CassetteReplay.
+	Mode CassetteMode
+	// Match lists the request parts compared when replaying. Defaults to
+	// method, path, query and body.
+	Match []MatchOn
+	// MatchHeaders lists request headers that must also match. Redacted
+	// headers are compared by their redacted value.
+	MatchHeaders []string
+}
+
+// defaultMatch is used when CassetteConfig.Match is empty.
+var defaultMatch = []MatchOn{MatchMethod, MatchPath, MatchQuery, MatchBody}
+
+// cassette holds the exchanges of one suite.
+type cassette struct {
+	config CassetteConfig
+	path   string
+	mode   CassetteMode
+	suite  *TestSuite
+
+	mu           sync.Mutex
+	interactions []*interaction
+	played       []bool
+}
+
+type cassetteFile struct {
+	Interactions []*interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Headers      http.Header `json:"headers,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"`
+}
+
+type cassetteResponse struct {
+	Status       int         `json:"status"`
+	Proto        string      `json:"proto"`
+	Headers      http.Header `json:"headers,omitempty"`
+	Trailers     http.Header `json:"trailers,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"`
+	// Truncated marks a body recorded only up to Config.MaxCaptureSize.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// cassetteMode resolves the mode from the environment, falling back to
+// config.
+func cassetteMode(config CassetteConfig) (CassetteMode, error) {
+	mode := config.Mode
+
+	if env := os.Getenv(cassetteModeEnv); env != "" {
+		mode = CassetteMode(env)
+	}
+
+	switch mode {
+	case "":
+		return CassetteReplay, nil
+	case CassetteReplay, CassetteRecord, CassettePassthrough:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown cassette mode %q; use record, replay or passthrough", mode)
+	}
+}
+
+// newCassette opens the suite's cassette, or returns nil in passthrough mode.
+// In replay mode the file must already exist.
+func newCassette(s *TestSuite) (*cassette, error) {
+	config := *s.config.Cassette
+
+	mode, err := cassetteMode(config)
+	if err != nil || mode == CassettePassthrough {
+		return nil, err
+	}
+
+	dir := config.Dir
+	if dir == "" {
+		dir = defaultCassetteDir
+	}
+
+	name := config.Name
+	if name == "" {
+		name = s.t.Name()
+	}
+
+	c := &cassette{
+		config: config,
+		path:   filepath.Join(dir, artifactName(name)+".json"),
+		mode:   mode,
+		suite:  s,
+	}
+
+	if mode == CassetteRecord {
+		s.t.Cleanup(c.write)
+
+		return c, nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette (record it with %s=record): %w", cassetteModeEnv, err)
+	}
+
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s: %w", c.path, err)
+	}
+
+	c.interactions = file.Interactions
+	c.played = make([]bool, len(file.Interactions))
+
+	return c, nil
+}
+
+// cassetteTransport sends requests through a cassette.
+type cassetteTransport struct {
+	cassette *cassette
+	next     http.RoundTripper
+}
+
+// RoundTrip replays or records req depending on the cassette mode.
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cassette.mode == CassetteReplay {
+		body, err := t.cassette.readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+
+		return t.cassette.replay(req, body)
+	}
+
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return t.cassette.record(next, req)
+}
+
+// captureLimit returns how many bytes of each body the cassette keeps.
+func (c *cassette) captureLimit() int64 {
+	return cmp.Or(c.suite.config.MaxCaptureSize, defaultMaxCapture)
+}
+
+// readRequestBody consumes the request body in replay mode, keeping the
+// part that is compared with recorded requests.
+func (c *cassette) readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+
+	capture := &captureWriter{limit: c.captureLimit()}
+	if _, err := io.Copy(capture, req.Body); err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	return capture.data, nil
+}
+
+// record sends req and adds the exchange to the cassette. The request body
+// is streamed as usual and captured as it is sent.
+func (c *cassette) record(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	var sent *teeBody
+
+	if req.Body != nil && req.Body != http.NoBody {
+		sent = &teeBody{ReadCloser: req.Body, capture: &captureWriter{limit: c.captureLimit()}}
+		req = req.Clone(req.Context())
+		req.Body = sent
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Transport errors are reported by the caller.
+	}
+
+	entry := &interaction{
+		Request: cassetteRequest{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: c.suite.redactHeaders(req.Header),
+		},
+		Response: cassetteResponse{
+			Status:  resp.StatusCode,
+			Proto:   resp.Proto,
+			Headers: c.suite.redactHeaders(resp.Header),
+		},
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, entry)
+	c.mu.Unlock()
+
+	// Event streams may never end, so only what the test read is saved;
+	// other bodies are read up to the capture limit on close so bodies the
+	// test never read are still recorded.
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	resp.Body = &recordingBody{
+		ReadCloser: resp.Body,
+		capture:    &captureWriter{limit: c.captureLimit()},
+		drain:      mediaType != "text/event-stream",
+		finish: func(capture *captureWriter) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+
+			entry.Response.Body, entry.Response.BodyEncoding = encodeCassetteBody(capture.data)
+			entry.Response.Truncated = capture.truncated
+
+			// The server may answer before the whole request body is sent.
+			if sent != nil {
+				entry.Request.Body, entry.Request.BodyEncoding = encodeCassetteBody(sent.data())
+			}
+
+			if len(resp.Trailer) > 0 {
+				entry.Response.Trailers = resp.Trailer.Clone()
+			}
+		},
+	}
+
+	return resp, nil
+}
+
+// replay answers req with the first recorded exchange that matches it and
+// has not been played yet. Once all matching exchanges have been played,
+// the last one is repeated, so polled requests keep getting a response.
+func (c *cassette) replay(req *http.Request, body []byte) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	found := -1
+
+	for i, entry := range c.interactions {
+		if !c.matches(entry.Request, req, body) {
+			continue
+		}
+
+		found = i
+		if !c.played[i] {
+			break
+		}
+	}
+
+	if found < 0 {
+		return nil, c.unmatched(req)
+	}
+
+	c.played[found] = true
+	entry := c.interactions[found]
+
+	if entry.Response.Truncated {
+		body, _ := decodeCassetteBody(entry.Response.Body, entry.Response.BodyEncoding)
+
+		return nil, fmt.Errorf("interaction %s %s in cassette %s has a body cut off at %d bytes by MaxCaptureSize "+
+			"(raise it and re-record with %s=record)", entry.Request.Method, entry.Request.URL, c.path, len(body), cassetteModeEnv)
+	}
+
+	return entry.Response.response(req)
+}
+
+// matches reports whether rec was recorded for a request like req.
+func (c *cassette) matches(rec cassetteRequest, req *http.Request, body []byte) bool {
+	recURL, err := url.Parse(rec.URL)
+	if err != nil {
+		return false
+	}
+
+	match := c.config.Match
+	if len(match) == 0 {
+		match = defaultMatch
+	}
+
+	for _, on := range match {
+		if !matchOn(on, rec, recURL, req, body) {
+			return false
+		}
+	}
+
+	headers := c.suite.redactHeaders(req.Header)
+	for _, name := range c.config.MatchHeaders {
+		if strings.Join(rec.Headers.Values(name), ",") != strings.Join(headers.Values(name), ",") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchOn compares one part of a recorded request with req.
+func matchOn(on MatchOn, rec cassetteRequest, recURL *url.URL, req *http.Request, body []byte) bool {
+	switch on {
+	case MatchMethod:
+		return rec.Method == req.Method
+	case MatchPath:
+		return recURL.Path == req.URL.Path
+	case MatchQuery:
+		return recURL.Query().Encode() == req.URL.Query().Encode()
+	case MatchBody:
+		recBody, err := decodeCassetteBody(rec.Body, rec.BodyEncoding)
+
+		return err == nil && bodiesEqual(recBody, body)
+	default:
+		return false
+	}
+}
+
+// bodiesEqual compares request bodies, treating JSON documents that differ
+// only in formatting or key order as equal.
+func bodiesEqual(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+
+	var aJSON, bJSON interface{}
+	if json.Unmarshal(a, &aJSON) != nil || json.Unmarshal(b, &bJSON) != nil {
+		return false
+	}
+
+	return jsonEqual(aJSON, bJSON)
+}
+
+// unmatched describes a request missing from the cassette along with what
+// was recorded.
+func (c *cassette) unmatched(req *http.Request) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "no interaction in cassette %s matches %s %s", c.path, req.Method, req.URL.RequestURI())
+	fmt.Fprintf(&b, " (re-record it with %s=record)", cassetteModeEnv)
+
+	if len(c.interactions) == 0 {
+		b.WriteString("; the cassette is empty")
+	}
+
+	for _, entry := range c.interactions {
+		fmt.Fprintf(&b, "\n  recorded: %s %s", entry.Request.Method, entry.Request.URL)
+	}
+
+	return errors.New(b.String())
+}
+
+// response rebuilds a recorded response for req.
+func (r cassetteResponse) response(req *http.Request) (*http.Response, error) {
+	body, err := decodeCassetteBody(r.Body, r.BodyEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode recorded response body: %w", err)
+	}
+
+	proto := r.Proto
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+
+	major, minor, _ := http.ParseHTTPVersion(proto)
+
+	header := r.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         proto,
+		ProtoMajor:    major,
+		ProtoMinor:    minor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		Trailer:       r.Trailers.Clone(),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// write saves the recorded exchanges when the test ends.
+func (c *cassette) write() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(cassetteFile{Interactions: c.interactions}, "", "  ")
+	if err != nil {
+		c.suite.t.Errorf("Failed to encode cassette: %v", err)
+
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o750); err != nil {
+		c.suite.t.Errorf("Failed to create cassette directory: %v", err)
+
+		return
+	}
+
+	if err := os.WriteFile(c.path, data, 0o600); err != nil {
+		c.suite.t.Errorf("Failed to write cassette: %v", err)
+	}
+}
+
+// encodeCassetteBody stores text bodies as is and binary bodies as base64.
+func encodeCassetteBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+// decodeCassetteBody reverses encodeCassetteBody.
+func decodeCassetteBody(text, encoding string) ([]byte, error) {
+	if encoding == "base64" {
+		data, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 body: %w", err)
+		}
+
+		return data, nil
+	}
+
+	return []byte(text), nil
+}
+
+// recordingBody keeps the head of a response body as it is read and hands
+// it to finish at EOF or when the body is closed. With drain set, Close
+// first reads the rest of the body up to the capture limit.
+type recordingBody struct {
+	io.ReadCloser
+	capture *captureWriter
+	drain   bool
+	finish  func(capture *captureWriter)
+	once    sync.Once
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	_, _ = b.capture.Write(p[:n])
+
+	if errors.Is(err, io.EOF) {
+		b.done()
+	}
+
+	return n, err //nolint:wrapcheck // Read must return io.EOF unwrapped.
+}
+
+// Close records the body read so far and closes the underlying body.
+func (b *recordingBody) Close() error {
+	if room := b.capture.limit - int64(len(b.capture.data)); b.drain && !b.capture.truncated {
+		// One byte past the limit tells a full capture from a truncated one.
+		_, _ = io.Copy(io.Discard, io.LimitReader(b, room+1))
+	}
+
+	b.done()
+
+	return b.ReadCloser.Close() //nolint:wrapcheck // Close errors are passed through unchanged.
+}
+
+func (b *recordingBody) done() {
+	b.once.Do(func() { b.finish(b.capture) })
+}
+
+// teeBody captures a request body as the transport sends it.
+type teeBody struct {
+	io.ReadCloser
+	mu      sync.Mutex
+	capture *captureWriter
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.mu.Lock()
+	_, _ = b.capture.Write(p[:n])
+	b.mu.Unlock()
+
+	return n, err //nolint:wrapcheck // Read must return io.EOF unwrapped.
+}
+
+// data returns the part of the body sent so far.
+func (b *teeBody) data() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return bytes.Clone(b.capture.data)
+}
@@ -0,0 +1,184 @@
+package e2e
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Encoding is an HTTP content coding.
+type Encoding string
+
+const (
+	// EncodingIdentity means no content coding.
+	EncodingIdentity Encoding = ""
+	// EncodingGzip is the gzip coding.
+	EncodingGzip Encoding = "gzip"
+	// EncodingDeflate is the deflate coding, a zlib stream as HTTP defines it.
+	EncodingDeflate Encoding = "deflate"
+	// EncodingBrotli is the Brotli coding.
+	EncodingBrotli Encoding = "br"
+	// EncodingZstd is the Zstandard coding.
+	EncodingZstd Encoding = "zstd"
+)
+
+// acceptEncoding is sent unless the request sets its own Accept-Encoding.
+// Setting it stops net/http from negotiating gzip and decoding it silently.
+const acceptEncoding = "gzip, deflate, br, zstd"
+
+// Compress encodes the request body with encoding and sets Content-Encoding.
+func (h *HTTPBuilder) Compress(encoding Encoding) *HTTPBuilder {
+	h.compression = encoding
+
+	return h
+}
+
+// Decompress controls whether a compressed response body is decoded before
+// assertions, overriding Config.DisableDecompression. When disabled, body
+// assertions, length and checksums see the bytes as sent by the server.
+func (h *HTTPBuilder) Decompress(enabled bool) *HTTPBuilder {
+	h.decompress = &enabled
+
+	return h
+}
+
+// ExpectContentEncoding validates the Content-Encoding the server sent.
+// EncodingIdentity expects the header to be absent or "identity".
+func (h *HTTPBuilder) ExpectContentEncoding(encoding Encoding) *HTTPBuilder {
+	return h.expect(func() error {
+		actual := h.resp.Header.Get("Content-Encoding")
+		if Encoding(actual) == encoding || (encoding == EncodingIdentity && actual == "identity") {
+			return nil
+		}
+
+		return errors.New(h.formatError("Content-Encoding mismatch", describeEncoding(encoding), describeEncoding(Encoding(actual))))
+	})
+}
+
+// describeEncoding formats an encoding for error reports.
+func describeEncoding(encoding Encoding) string {
+	if encoding == EncodingIdentity {
+		return "(none)"
+	}
+
+	return string(encoding)
+}
+
+// decompressEnabled reports whether response bodies should be decoded.
+func (h *HTTPBuilder) decompressEnabled() bool {
+	if h.decompress != nil {
+		return *h.decompress
+	}
+
+	return !h.suite.config.DisableDecompression
+}
+
+// responseReader returns the response body, decoded according to its
+// Content-Encoding when decompression is enabled.
+func (h *HTTPBuilder) responseReader() (io.Reader, error) {
+	header := h.resp.Header.Get("Content-Encoding")
+	if header == "" || !h.decompressEnabled() {
+		return h.resp.Body, nil
+	}
+
+	codings := strings.Split(header, ",")
+
+	var r io.Reader = h.resp.Body
+
+	// Codings are listed in the order they were applied, so undo them in reverse.
+	for i := len(codings) - 1; i >= 0; i-- {
+		decoded, err := decoder(Encoding(strings.TrimSpace(codings[i])), r)
+		if err != nil {
+			return nil, err
+		}
+
+		r = decoded
+	}
+
+	return r, nil
+}
+
+// decoder wraps r to decode encoding. An empty stream, as sent for HEAD
+// requests, decodes to an empty body.
+func decoder(encoding Encoding, r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+	if _, err := buffered.Peek(1); errors.Is(err, io.EOF) {
+		return buffered, nil
+	}
+
+	var (
+		decoded io.Reader
+		err     error
+	)
+
+	switch encoding {
+	case EncodingGzip, "x-gzip":
+		decoded, err = gzip.NewReader(buffered)
+	case EncodingDeflate:
+		decoded, err = zlib.NewReader(buffered)
+	case EncodingBrotli:
+		decoded = brotli.NewReader(buffered)
+	case EncodingZstd:
+		// A single-threaded decoder runs without goroutines, so it needs no Close.
+		decoded, err = zstd.NewReader(buffered, zstd.WithDecoderConcurrency(1))
+	case EncodingIdentity, "identity":
+		decoded = buffered
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding %q; disable decompression to assert on the raw body", encoding)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s body: %w", encoding, err)
+	}
+
+	return decoded, nil
+}
+
+// compress encodes data with encoding.
+func compress(encoding Encoding, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w, err := encoder(encoding, &buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress body: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress body: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encoder returns a writer encoding to w with encoding.
+func encoder(encoding Encoding, w io.Writer) (io.WriteCloser, error) {
+	switch encoding {
+	case EncodingGzip:
+		return gzip.NewWriter(w), nil
+	case EncodingDeflate:
+		return zlib.NewWriter(w), nil
+	case EncodingBrotli:
+		return brotli.NewWriter(w), nil
+	case EncodingZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+		}
+
+		return zw, nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", encoding)
+	}
+}
@@ -0,0 +1,124 @@
+package e2e
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CoverageOptions collects Go coverage from a process started with
+// StartProcess. The binary is built with -cover and run with GOCOVERDIR;
+// when it stops, every run recorded in the data directory is merged into a
+// text profile for go tool cover. The process must exit normally, such as
+// by returning from main on SIGINT, for its coverage to be written.
+type CoverageOptions struct {
+	// Profile is the text profile to write, such as "e2e.cover.out".
+	Profile string
+	// Dir holds the raw coverage data. Processes sharing a Dir are merged
+	// into one profile. It is emptied the first time a test binary uses it.
+	// Defaults to Profile with a ".covdata" suffix.
+	Dir string
+	// Packages limits instrumentation to the listed package patterns,
+	// passed as -coverpkg. Defaults to the main module's packages.
+	Packages []string
+	// Mode is the -covermode, such as "atomic". Use the mode of your unit
+	// test profiles to merge them.
+	Mode string
+}
+
+// coverageDirs tracks the data directories emptied by this test binary.
+var coverageDirs = struct {
+	sync.Mutex
+	cleared map[string]bool
+}{cleared: make(map[string]bool)}
+
+// buildFlags returns the go build flags that instrument the binary.
+func (c *CoverageOptions) buildFlags() []string {
+	flags := []string{"-cover"}
+
+	if len(c.Packages) > 0 {
+		flags = append(flags, "-coverpkg="+strings.Join(c.Packages, ","))
+	}
+
+	if c.Mode != "" {
+		flags = append(flags, "-covermode="+c.Mode)
+	}
+
+	return flags
+}
+
+// dataDir returns the absolute data directory, creating it and removing
+// data left by earlier test runs on first use.
+func (c *CoverageOptions) dataDir() (string, error) {
+	if c.Profile == "" {
+		return "", errors.New("coverage requires a Profile")
+	}
+
+	dir, err := filepath.Abs(cmp.Or(c.Dir, c.Profile+".covdata"))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve coverage directory: %w", err)
+	}
+
+	coverageDirs.Lock()
+	defer coverageDirs.Unlock()
+
+	if !coverageDirs.cleared[dir] {
+		if err := os.RemoveAll(dir); err != nil {
+			return "", fmt.Errorf("failed to clear coverage directory: %w", err)
+		}
+
+		coverageDirs.cleared[dir] = true
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create coverage directory: %w", err)
+	}
+
+	return dir, nil
+}
+
+// instrument adds the coverage build flags and GOCOVERDIR to opts,
+// returning the data directory.
+func (c *CoverageOptions) instrument(opts *ProcessOptions) (string, error) {
+	dir, err := c.dataDir()
+	if err != nil {
+		return "", err
+	}
+
+	opts.BuildFlags = append(c.buildFlags(), opts.BuildFlags...)
+	opts.Env = append(slices.Clone(opts.Env), "GOCOVERDIR="+dir)
+
+	return dir, nil
+}
+
+// writeCoverage merges the data directory into the text profile.
+func (p *Process) writeCoverage() {
+	profile, err := filepath.Abs(p.coverage.Profile)
+	if err != nil {
+		p.tb.Errorf("Failed to resolve coverage profile: %v", err)
+
+		return
+	}
+
+	// Processes sharing a directory may stop concurrently.
+	coverageDirs.Lock()
+	defer coverageDirs.Unlock()
+
+	// The test context is already done while cleanups run.
+	cmd := exec.CommandContext(context.Background(), "go", "tool", "covdata", "textfmt", "-i="+p.coverDir, "-o="+profile)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		p.tb.Errorf("Failed to write coverage profile for %s: %v\n%s", p.name, err, out)
+
+		return
+	}
+
+	p.tb.Logf("Coverage of %s written to %s", p.name, profile)
+}
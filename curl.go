@@ -0,0 +1,294 @@
+package e2e
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// curlTLSVersions maps minimum TLS versions to curl flags.
+var curlTLSVersions = map[uint16]string{
+	tls.VersionTLS10: "--tlsv1.0",
+	tls.VersionTLS11: "--tlsv1.1",
+	tls.VersionTLS12: "--tlsv1.2",
+	tls.VersionTLS13: "--tlsv1.3",
+}
+
+// curlEncoders compress a body on the command line like Compress does.
+var curlEncoders = map[Encoding]string{
+	EncodingGzip:    "gzip -c",
+	EncodingDeflate: "pigz -zc",
+	EncodingBrotli:  "brotli -c",
+	EncodingZstd:    "zstd -c",
+}
+
+// formatReproduce returns the curl command that repeats the request for a
+// failure report, naming the script fail saves when Config.CurlDir is set.
+func (h *HTTPBuilder) formatReproduce() string {
+	command := h.curlCommand()
+
+	var sb strings.Builder
+
+	sb.WriteString("\nReproduce:\n")
+
+	for _, line := range strings.Split(command, "\n") {
+		sb.WriteString("  " + line + "\n")
+	}
+
+	if h.suite.config.CurlDir != "" {
+		fmt.Fprintf(&sb, "Script:   %s\n", h.suite.curlScriptPath())
+	}
+
+	return sb.String()
+}
+
+// fail saves the curl script when Config.CurlDir is set and fails the test
+// with msg. Reports built for failed polls are not final, so only the sites
+// that stop the test call it.
+func (h *HTTPBuilder) fail(msg string) {
+	if h.suite.config.CurlDir != "" {
+		if err := h.suite.writeCurlScript(h.curlCommand()); err != nil {
+			msg += fmt.Sprintf("Script error: %v\n", err)
+		}
+	}
+
+	h.suite.t.Fatal(msg)
+}
+
+// curlScriptPath returns <CurlDir>/<test name>.sh.
+func (s *TestSuite) curlScriptPath() string {
+	return filepath.Join(s.config.CurlDir, artifactName(s.t.Name())+".sh")
+}
+
+// writeCurlScript saves command to curlScriptPath.
+func (s *TestSuite) writeCurlScript(command string) error {
+	if err := os.MkdirAll(s.config.CurlDir, 0o750); err != nil {
+		return fmt.Errorf("failed to create curl directory: %w", err)
+	}
+
+	//nolint:gosec // The script is meant to be run by whoever reads the report.
+	if err := os.WriteFile(s.curlScriptPath(), []byte("#!/usr/bin/env bash\n"+command+"\n"), 0o700); err != nil {
+		return fmt.Errorf("failed to write curl script: %w", err)
+	}
+
+	return nil
+}
+
+// curlCommand builds a curl command line equivalent to the request that was
+// sent. Redacted headers keep their placeholder, and settings curl cannot
+// express are listed as comments above the command.
+func (h *HTTPBuilder) curlCommand() string {
+	args := []string{"curl"}
+
+	switch h.method {
+	case http.MethodGet:
+	case http.MethodHead:
+		args = append(args, "--head")
+	default:
+		args = append(args, "--request "+h.method)
+	}
+
+	transportArgs, notes := h.curlTransportArgs()
+	args = append(args, transportArgs...)
+	args = append(args, h.curlHeaderArgs()...)
+
+	pipe, bodyArgs, bodyNotes := h.curlBodyArgs()
+	args = append(args, bodyArgs...)
+	notes = append(notes, bodyNotes...)
+
+	if len(h.redirects) > 0 && (h.followRedirects == nil || *h.followRedirects) {
+		args = append(args, "--location")
+	}
+
+	args = append(args, shellQuote(h.requestURL))
+
+	var sb strings.Builder
+
+	for _, note := range notes {
+		sb.WriteString("# " + note + "\n")
+	}
+
+	sb.WriteString(pipe)
+	sb.WriteString(strings.Join(args, " \\\n  "))
+
+	return sb.String()
+}
+
+// curlHeaderArgs returns the request headers as -H flags, in name order.
+func (h *HTTPBuilder) curlHeaderArgs() []string {
+	var args []string
+
+	headers := h.suite.redactHeaders(h.requestHeaders)
+
+	// The default Accept-Encoding is what --compressed sends, and makes
+	// curl decode the response like the suite does.
+	if headers.Get("Accept-Encoding") == acceptEncoding {
+		headers.Del("Accept-Encoding")
+
+		args = append(args, "--compressed")
+	}
+
+	if h.chunked {
+		headers.Set("Transfer-Encoding", "chunked")
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		for _, value := range headers[name] {
+			args = append(args, "-H "+shellQuote(name+": "+value))
+		}
+	}
+
+	return args
+}
+
+// curlBodyArgs returns the body flags, plus a pipeline prefix that
+// compresses the body when Compress is set.
+func (h *HTTPBuilder) curlBodyArgs() (string, []string, []string) {
+	data := h.requestBody
+
+	var notes []string
+
+	if h.sent != nil {
+		data = h.sent.data
+
+		if h.sent.truncated {
+			notes = append(notes, fmt.Sprintf("Only the first %d of %d streamed body bytes are included.", len(data), h.sent.written))
+		}
+	}
+
+	if len(data) == 0 {
+		return "", nil, notes
+	}
+
+	if encoder, ok := curlEncoders[h.compression]; ok {
+		return fmt.Sprintf("printf '%%s' %s | %s | ", shellQuote(string(data)), encoder), []string{"--data-binary @-"}, notes
+	}
+
+	return "", []string{"--data-binary " + shellQuote(string(data))}, notes
+}
+
+// curlTransportArgs returns the flags for the protocol, dial target and TLS
+// settings, with notes for settings curl cannot express.
+func (h *HTTPBuilder) curlTransportArgs() ([]string, []string) {
+	var args, notes []string
+
+	config := h.suite.config
+
+	protocol := h.protocol
+	if protocol == ProtocolAuto {
+		protocol = config.Protocol
+	}
+
+	switch protocol {
+	case ProtocolHTTP1:
+		args = append(args, "--http1.1")
+	case ProtocolHTTP2:
+		args = append(args, "--http2")
+	case ProtocolH2C:
+		args = append(args, "--http2-prior-knowledge")
+	case ProtocolAuto:
+	}
+
+	switch {
+	case config.Handler != nil:
+		notes = append(notes, "The request was served in-process by Config.Handler; point the URL at a running server.")
+	case config.DialContext != nil:
+		notes = append(notes, "Config.DialContext chose the connection; adjust the URL to reach the same server.")
+	case config.Target != "":
+		if network, address, err := parseTarget(config.Target); err == nil {
+			if network == "unix" {
+				args = append(args, "--unix-socket "+shellQuote(address))
+			} else {
+				args = append(args, "--connect-to "+shellQuote("::"+address))
+			}
+		}
+	}
+
+	if config.TLS != nil {
+		tlsArgs, tlsNotes := config.TLS.curlArgs()
+		args = append(args, tlsArgs...)
+		notes = append(notes, tlsNotes...)
+	}
+
+	return args, notes
+}
+
+// curlArgs returns the curl flags for the TLS settings.
+func (c *TLSConfig) curlArgs() ([]string, []string) {
+	var args, notes []string
+
+	if c.CAFile != "" {
+		args = append(args, "--cacert "+shellQuote(c.CAFile))
+	}
+
+	if len(c.CAPEM) > 0 {
+		notes = append(notes, "TLSConfig.CAPEM is not on disk; save it and pass --cacert.")
+	}
+
+	if c.CertFile != "" {
+		args = append(args, "--cert "+shellQuote(c.CertFile), "--key "+shellQuote(c.KeyFile))
+	}
+
+	if len(c.CertPEM) > 0 {
+		notes = append(notes, "TLSConfig.CertPEM is not on disk; save it and pass --cert and --key.")
+	}
+
+	if flag, ok := curlTLSVersions[c.MinVersion]; ok {
+		args = append(args, flag)
+	}
+
+	if c.InsecureSkipVerify {
+		args = append(args, "--insecure")
+	}
+
+	if c.ServerName != "" {
+		notes = append(notes, fmt.Sprintf("TLS server name %s is not set; curl uses the URL host.", c.ServerName))
+	}
+
+	return args, notes
+}
+
+// shellQuote quotes s for bash. Text with control characters or invalid
+// UTF-8 uses ANSI-C quoting so every byte survives.
+func shellQuote(s string) string {
+	printable := utf8.ValidString(s) && strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsControl(r) && r != '\n' && r != '\t'
+	}) < 0
+
+	if printable {
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("$'")
+
+	for i := range len(s) {
+		switch c := s[i]; {
+		case c == '\'' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&sb, `\x%02x`, c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	sb.WriteString("'")
+
+	return sb.String()
+}
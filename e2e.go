@@ -4,19 +4,76 @@ package e2e
 import (
 	"bytes"
 	"context"
+	"crypto"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
 // Config holds configuration for the test suite.
 type Config struct {
 	BaseURL string
 	Timeout time.Duration // Default timeout for requests
+
+	// Client is used to send every request. When nil, a client is built
+	// around Transport.
+	Client *http.Client
+	// Transport is used when Client is nil. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// Retry is the default retry policy for every request. Nil disables retries.
+	Retry *RetryPolicy
+	// TLS configures certificates and TLS versions for the transport.
+	TLS *TLSConfig
+	// Protocol selects HTTP/1.1, HTTP/2 or h2c. Defaults to ProtocolAuto.
+	Protocol Protocol
+	// Target overrides where connections are dialed, such as
+	// "unix:///run/app.sock" or "tcp://127.0.0.1:8080". BaseURL still
+	// controls the Host header and path resolution.
+	Target string
+	// DialContext dials every connection, taking precedence over Target.
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	// Handler serves requests in-process instead of over the network.
+	// BaseURL defaults to http://example.com in this mode.
+	Handler http.Handler
+	// GRPC configures gRPC calls made with TestSuite.GRPC.
+	GRPC *GRPCConfig
+	// GraphQLPath is the endpoint for TestSuite.GraphQL. Defaults to /graphql.
+	GraphQLPath string
+	// RPCPath is the endpoint for TestSuite.RPC. Defaults to BaseURL itself.
+	RPCPath string
+	// MaxCaptureSize is how many bytes of each response body are kept in
+	// memory for assertions and error reports. Defaults to 10 MiB.
+	MaxCaptureSize int64
+	// DisableDecompression keeps compressed response bodies encoded, so
+	// assertions see the bytes as sent.
+	DisableDecompression bool
+	// HARDir, when set, records every exchange into a HAR 1.2 file per
+	// test, named after the test, written when the test ends.
+	HARDir string
+	// RedactHeaders lists headers whose values are replaced in recorded
+	// artifacts. Defaults to Authorization, Cookie, Proxy-Authorization and
+	// Set-Cookie; set an empty slice to record everything.
+	RedactHeaders []string
+	// Cassette records exchanges to a file and replays them in later runs.
+	Cassette *CassetteConfig
+	// CurlDir, when set, saves the curl command printed with each failure
+	// report as an executable script named after the test.
+	CurlDir string
+	// WaitFor makes New block until the service passes the health check,
+	// failing the test with the last probe's result if it never does.
+	WaitFor *HealthCheck
 }
 
 // TestSuite represents the main test suite.
@@ -24,24 +81,67 @@ type TestSuite struct {
 	config Config
 	t      testing.TB
 	client *http.Client
+
+	mu         sync.Mutex
+	transports map[Protocol]http.RoundTripper
+	grpcClient *grpc.ClientConn
+	grpcFiles  *protoregistry.Files
+	// rpcID generates JSON-RPC request ids.
+	rpcID atomic.Int64
+	// har collects exchanges when Config.HARDir is set.
+	har *harRecorder
+	// cassette records or replays exchanges when Config.Cassette is set.
+	cassette *cassette
 }
 
 // HTTPBuilder builds HTTP requests.
 type HTTPBuilder struct {
-	suite   *TestSuite
-	method  string
-	path    string
-	body    interface{}
-	headers map[string]string
-	query   map[string]string
-	timeout time.Duration
-	resp    *http.Response
+	suite    *TestSuite
+	method   string
+	path     string
+	body     interface{}
+	headers  map[string]string
+	query    map[string]string
+	timeout  time.Duration
+	retry    *RetryPolicy
+	protocol Protocol
+	// Redirect policy; nil followRedirects follows like net/http.
+	followRedirects *bool
+	maxRedirects    int
+	// eventually is set when assertions should be polled until they pass.
+	eventually *eventually
+	resp       *http.Response
+	// cancel ends the context of the current response; released records
+	// that the end-of-test cleanup for both has been registered.
+	cancel   context.CancelFunc
+	released bool
+	// sse is set once the response is consumed as an event stream.
+	sse *SSEStream
+	// graphql adds a summary of GraphQL errors to error reports.
+	graphql bool
+	// Body streaming options; see MaxCapture, Checksum and Range.
+	maxCapture int64
+	checksums  []crypto.Hash
+	byteRange  *byteRange
+	// Content coding; see Compress and Decompress.
+	compression Encoding
+	decompress  *bool
+	// Request body transfer; see ContentLength, Chunked and Throttle.
+	contentLength *int64
+	chunked       bool
+	throttle      int
+	streamSent    bool
 
 	// Request details for error reporting
 	requestURL     string
 	requestHeaders http.Header
 	requestBody    []byte
+	encodedBody    []byte
+	sent           *captureWriter
 	responseBody   []byte
+	bodyStats      *bodyStats
+	attempts       []attempt
+	redirects      []RedirectHop
 }
 
 // New creates a new test suite.
@@ -53,13 +153,35 @@ func New(tb testing.TB, config Config) *TestSuite {
 		config.Timeout = 30 * time.Second
 	}
 
-	return &TestSuite{
+	if config.Handler != nil && config.BaseURL == "" {
+		config.BaseURL = defaultHandlerBaseURL
+	}
+
+	client, err := newClient(config)
+	if err != nil {
+		tb.Fatalf("Failed to configure HTTP client: %v", err)
+	}
+
+	suite := &TestSuite{
 		config: config,
 		t:      tb,
-		client: &http.Client{
-			Timeout: config.Timeout,
-		},
+		client: client,
+	}
+
+	if config.Cassette != nil {
+		suite.cassette, err = newCassette(suite)
+		if err != nil {
+			tb.Fatalf("Failed to open cassette: %v", err)
+		}
+	}
+
+	if config.WaitFor != nil {
+		if err := suite.waitHealthy(*config.WaitFor); err != nil {
+			tb.Fatal("Service is not healthy: " + err.Error())
+		}
 	}
+
+	return suite
 }
 
 // GET creates a GET request builder.
@@ -125,7 +247,10 @@ func (s *TestSuite) OPTIONS(path string) *HTTPBuilder {
 	}
 }
 
-// Body sets the request body (accepts string, struct, or map).
+// Body sets the request body. Strings and []byte are sent as-is, an
+// io.Reader is streamed once, a func(io.Writer) error generates the body on
+// every attempt, and any other value is marshaled as JSON. Streamed bodies
+// are sent chunked unless ContentLength is set.
 func (h *HTTPBuilder) Body(body interface{}) *HTTPBuilder {
 	h.body = body
 
@@ -159,7 +284,8 @@ func (h *HTTPBuilder) Authorization(value string) *HTTPBuilder {
 	return h.Header("Authorization", value)
 }
 
-// Timeout sets a custom timeout for this specific request.
+// Timeout sets a custom timeout for this specific request, overriding
+// Config.Timeout. It is applied through the request context.
 func (h *HTTPBuilder) Timeout(timeout time.Duration) *HTTPBuilder {
 	h.timeout = timeout
 
@@ -168,50 +294,77 @@ func (h *HTTPBuilder) Timeout(timeout time.Duration) *HTTPBuilder {
 
 // Execute performs the HTTP request.
 func (h *HTTPBuilder) Execute(ctx context.Context) *HTTPBuilder {
+	if h.eventually != nil {
+		h.eventually.start(ctx, h)
+
+		return h
+	}
+
+	if err := h.execute(ctx); err != nil {
+		h.suite.t.Fatal(err.Error())
+	}
+
+	return h
+}
+
+// execute builds and sends the request, storing the response on success.
+func (h *HTTPBuilder) execute(ctx context.Context) error {
+	h.closeResponse()
+
+	if !h.released {
+		h.suite.t.Cleanup(h.release)
+		h.released = true
+	}
+
 	reqURL := h.buildURL()
-	bodyReader := h.prepareBody()
+	h.prepareBody()
 	ctx = h.applyTimeout(ctx)
-	req := h.createRequest(ctx, reqURL, bodyReader)
-	h.setHeaders(req)
 
 	// Store request details for error reporting
 	h.requestURL = reqURL.String()
-	h.requestHeaders = req.Header.Clone()
-
-	h.executeRequest(req, reqURL)
 
-	return h
+	return h.executeRequest(ctx, reqURL)
 }
 
 func (h *HTTPBuilder) buildURL() *url.URL {
-	baseURL, err := url.Parse(h.suite.config.BaseURL)
+	reqURL, err := h.suite.resolveURL(h.path, h.query)
+	if err != nil {
+		h.suite.t.Fatal(err.Error())
+	}
+
+	return reqURL
+}
+
+// resolveURL resolves path against the base URL and adds query parameters.
+func (s *TestSuite) resolveURL(path string, query map[string]string) (*url.URL, error) {
+	baseURL, err := url.Parse(s.config.BaseURL)
 	if err != nil {
-		h.suite.t.Fatalf("Failed to parse base URL: %v", err)
+		return nil, fmt.Errorf("failed to parse base URL: %w", err)
 	}
 
-	path, err := url.Parse(h.path)
+	ref, err := url.Parse(path)
 	if err != nil {
-		h.suite.t.Fatalf("Failed to parse path: %v", err)
+		return nil, fmt.Errorf("failed to parse path: %w", err)
 	}
 
-	reqURL := baseURL.ResolveReference(path)
+	reqURL := baseURL.ResolveReference(ref)
 
 	// Add query parameters
-	if len(h.query) > 0 {
+	if len(query) > 0 {
 		q := reqURL.Query()
-		for key, value := range h.query {
+		for key, value := range query {
 			q.Add(key, value)
 		}
 
 		reqURL.RawQuery = q.Encode()
 	}
 
-	return reqURL
+	return reqURL, nil
 }
 
-func (h *HTTPBuilder) prepareBody() io.Reader {
-	if h.body == nil {
-		return nil
+func (h *HTTPBuilder) prepareBody() {
+	if h.body == nil || h.isStreamBody() {
+		return
 	}
 
 	bodyBytes, err := h.serializeBody()
@@ -222,139 +375,272 @@ func (h *HTTPBuilder) prepareBody() io.Reader {
 	// Store request body for error reporting
 	h.requestBody = bodyBytes
 
-	return bytes.NewBuffer(bodyBytes)
+	if h.compression != EncodingIdentity {
+		h.encodedBody, err = compress(h.compression, bodyBytes)
+		if err != nil {
+			h.suite.t.Fatalf("Failed to compress body: %v", err)
+		}
+	}
+}
+
+// requestTimeout returns the timeout applied to the request context.
+func (h *HTTPBuilder) requestTimeout() time.Duration {
+	if h.timeout > 0 {
+		return h.timeout
+	}
+
+	return h.suite.config.Timeout
 }
 
 func (h *HTTPBuilder) applyTimeout(ctx context.Context) context.Context {
-	if h.timeout <= 0 {
+	timeout := h.requestTimeout()
+	if timeout <= 0 {
 		return ctx
 	}
 
-	newCtx, cancel := context.WithTimeout(ctx, h.timeout)
-	h.suite.t.Cleanup(cancel)
+	newCtx, cancel := context.WithTimeout(ctx, timeout)
+	h.cancel = cancel
 
 	return newCtx
 }
 
-func (h *HTTPBuilder) createRequest(ctx context.Context, reqURL *url.URL, bodyReader io.Reader) *http.Request {
-	req, err := http.NewRequestWithContext(ctx, h.method, reqURL.String(), bodyReader)
+func (h *HTTPBuilder) createRequest(ctx context.Context, reqURL *url.URL) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, h.method, reqURL.String(), nil)
 	if err != nil {
 		h.suite.t.Fatalf("Failed to create request: %v", err)
 	}
 
-	return req
+	if err := h.attachBody(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
 }
 
 func (h *HTTPBuilder) setHeaders(req *http.Request) {
-	if h.body != nil {
+	switch h.body.(type) {
+	case nil:
+	case []byte, io.Reader, func(io.Writer) error:
+		req.Header.Set("Content-Type", "application/octet-stream")
+	default:
 		req.Header.Set("Content-Type", "application/json")
 	}
 
+	if h.body != nil && h.compression != EncodingIdentity {
+		req.Header.Set("Content-Encoding", string(h.compression))
+	}
+
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+
 	for key, value := range h.headers {
 		req.Header.Set(key, value)
 	}
 }
 
-func (h *HTTPBuilder) executeRequest(req *http.Request, reqURL *url.URL) {
-	client := h.suite.client
-	if h.timeout > 0 {
-		client = &http.Client{
-			Timeout: h.timeout,
+func (h *HTTPBuilder) executeRequest(ctx context.Context, reqURL *url.URL) error {
+	policy := h.retryPolicy()
+	h.attempts = nil
+
+	client, err := h.httpClient()
+	if err != nil {
+		return fmt.Errorf("failed to configure %s request: %w", h.method, err)
+	}
+
+	for number := 1; ; number++ {
+		req, err := h.createRequest(ctx, reqURL)
+		if err != nil {
+			return h.finishRequest(reqURL, nil, err)
+		}
+
+		h.setHeaders(req)
+		h.requestHeaders = req.Header.Clone()
+		h.redirects = nil
+
+		req, timer := h.traceRequest(req)
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		record := attempt{number: number, err: err, duration: time.Since(start)}
+
+		if resp != nil {
+			record.statusCode = resp.StatusCode
+		}
+
+		// A wait that would outlast the deadline keeps the last response
+		// instead of failing while asleep.
+		wait, retry := policy.shouldRetry(number, resp, err)
+		if !retry || !h.bodyReplayable() || !waitFits(ctx, wait) {
+			h.attempts = append(h.attempts, record)
+
+			return h.completeRequest(reqURL, resp, err, timer)
+		}
+
+		record.wait = wait
+		h.attempts = append(h.attempts, record)
+		h.recordRetriedHAR(timer, resp, record)
+
+		if resp != nil {
+			discardBody(resp)
+		}
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return h.finishRequest(reqURL, nil, err)
 		}
 	}
+}
 
-	resp, err := client.Do(req)
+// finishRequest reports a transport error or stores the response.
+func (h *HTTPBuilder) finishRequest(reqURL *url.URL, resp *http.Response, err error) error {
 	if err != nil {
-		h.suite.t.Fatalf("Failed to execute %s request to %s: %v", h.method, reqURL.String(), err)
+		return fmt.Errorf("failed to execute %s request to %s: %w%s%s",
+			h.method, reqURL.String(), err, h.formatRedirects(), h.formatAttempts())
 	}
 
-	h.suite.t.Cleanup(func() {
-		if err := resp.Body.Close(); err != nil {
+	h.resp = resp
+
+	return nil
+}
+
+// release closes the last response and cancels its context when the test
+// ends. Earlier responses are released by closeResponse.
+func (h *HTTPBuilder) release() {
+	if h.resp != nil {
+		if err := h.resp.Body.Close(); err != nil {
 			h.suite.t.Logf("Failed to close response body: %v", err)
 		}
-	})
+	}
 
-	h.resp = resp
+	if h.cancel != nil {
+		h.cancel()
+	}
 }
 
-// ExpectStatus validates the response status code.
-func (h *HTTPBuilder) ExpectStatus(statusCode int) *HTTPBuilder {
-	if h.resp == nil {
-		h.suite.t.Fatal("Request not executed. Call Execute() first.")
+// closeResponse releases the current response before the request is re-executed.
+func (h *HTTPBuilder) closeResponse() {
+	switch {
+	case h.resp == nil:
+	case h.sse != nil:
+		// Draining a live event stream would block until the server ends it.
+		_ = h.resp.Body.Close()
+	default:
+		discardBody(h.resp)
 	}
 
-	if h.resp.StatusCode != statusCode {
-		expected := fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode))
-		actual := fmt.Sprintf("%d %s", h.resp.StatusCode, http.StatusText(h.resp.StatusCode))
-		h.suite.t.Fatal(h.formatError("Status code mismatch", expected, actual))
+	if h.cancel != nil {
+		h.cancel()
 	}
 
-	return h
+	h.cancel = nil
+	h.resp = nil
+	h.responseBody = nil
+	h.bodyStats = nil
+	h.sse = nil
 }
 
-// ExpectJSON validates the JSON response body.
-func (h *HTTPBuilder) ExpectJSON(expected interface{}) *HTTPBuilder {
+// expect runs an assertion against the response, failing the test when it
+// returns an error. In Eventually mode the request is re-executed instead.
+func (h *HTTPBuilder) expect(check func() error) *HTTPBuilder {
+	if h.eventually != nil {
+		h.eventually.await(h, check)
+
+		return h
+	}
+
 	if h.resp == nil {
 		h.suite.t.Fatal("Request not executed. Call Execute() first.")
 	}
 
-	body := h.readResponseBody()
-
-	// Parse actual response
-	var actual interface{}
-	if err := json.Unmarshal(body, &actual); err != nil {
-		h.suite.t.Fatalf("Failed to parse JSON response: %v. Body: %s", err, string(body))
+	if err := check(); err != nil {
+		h.fail(err.Error())
 	}
 
-	// Compare based on expected type
-	switch exp := expected.(type) {
-	case string:
-		// If expected is a JSON string, parse it first
-		var expectedParsed interface{}
-		if err := json.Unmarshal([]byte(exp), &expectedParsed); err != nil {
-			h.suite.t.Fatalf("Failed to parse expected JSON: %v", err)
-		}
+	return h
+}
 
-		if !jsonEqual(expectedParsed, actual) {
-			expectedJSON, _ := json.MarshalIndent(expectedParsed, "", "  ")
-			actualJSON, _ := json.MarshalIndent(actual, "", "  ")
-			h.suite.t.Fatal(h.formatError("JSON response mismatch", string(expectedJSON), string(actualJSON)))
+// ExpectStatus validates the response status code.
+func (h *HTTPBuilder) ExpectStatus(statusCode int) *HTTPBuilder {
+	return h.expect(func() error {
+		if h.resp.StatusCode == statusCode {
+			return nil
 		}
-	default:
-		// Marshal expected to JSON and back to normalize it
-		expectedBytes, err := json.Marshal(expected)
+
+		expected := fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode))
+		actual := fmt.Sprintf("%d %s", h.resp.StatusCode, http.StatusText(h.resp.StatusCode))
+
+		return errors.New(h.formatError("Status code mismatch", expected, actual))
+	})
+}
+
+// ExpectJSON validates the JSON response body.
+func (h *HTTPBuilder) ExpectJSON(expected interface{}) *HTTPBuilder {
+	return h.expect(func() error {
+		body, err := h.completeBody()
 		if err != nil {
-			h.suite.t.Fatalf("Failed to marshal expected value: %v", err)
+			return err
 		}
 
-		var expectedNormalized interface{}
-		if err := json.Unmarshal(expectedBytes, &expectedNormalized); err != nil {
-			h.suite.t.Fatalf("Failed to normalize expected value: %v", err)
+		// Parse actual response
+		var actual interface{}
+		if err := json.Unmarshal(body, &actual); err != nil {
+			return fmt.Errorf("failed to parse JSON response: %w. Body: %s", err, string(body))
+		}
+
+		expectedNormalized, err := normalizeJSON(expected)
+		if err != nil {
+			return err
 		}
 
 		if !jsonEqual(expectedNormalized, actual) {
 			expectedJSON, _ := json.MarshalIndent(expectedNormalized, "", "  ")
 			actualJSON, _ := json.MarshalIndent(actual, "", "  ")
-			h.suite.t.Fatal(h.formatError("JSON response mismatch", string(expectedJSON), string(actualJSON)))
+
+			return errors.New(h.formatError("JSON response mismatch", string(expectedJSON), string(actualJSON)))
 		}
-	}
 
-	return h
+		return nil
+	})
 }
 
 // ExpectHeader validates a response header.
 func (h *HTTPBuilder) ExpectHeader(key, value string) *HTTPBuilder {
-	if h.resp == nil {
-		h.suite.t.Fatal("Request not executed. Call Execute() first.")
-	}
+	return h.expect(func() error {
+		actualValue := h.resp.Header.Get(key)
+		if actualValue == value {
+			return nil
+		}
 
-	actualValue := h.resp.Header.Get(key)
-	if actualValue != value {
 		assertion := fmt.Sprintf("Header mismatch (%s)", key)
-		h.suite.t.Fatal(h.formatError(assertion, value, actualValue))
+
+		return errors.New(h.formatError(assertion, value, actualValue))
+	})
+}
+
+// normalizeJSON converts an expected value into its generic JSON form.
+// Strings are parsed as JSON documents; other values are marshaled and
+// unmarshaled so they compare equal to decoded response bodies.
+func normalizeJSON(expected interface{}) (interface{}, error) {
+	var normalized interface{}
+
+	// If expected is a JSON string, parse it first
+	if exp, ok := expected.(string); ok {
+		if err := json.Unmarshal([]byte(exp), &normalized); err != nil {
+			return nil, fmt.Errorf("failed to parse expected JSON: %w", err)
+		}
+
+		return normalized, nil
 	}
 
-	return h
+	// Marshal expected to JSON and back to normalize it
+	expectedBytes, err := json.Marshal(expected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal expected value: %w", err)
+	}
+
+	if err := json.Unmarshal(expectedBytes, &normalized); err != nil {
+		return nil, fmt.Errorf("failed to normalize expected value: %w", err)
+	}
+
+	return normalized, nil
 }
 
 // jsonEqual compares two JSON values for equality.
@@ -371,9 +657,12 @@ func (h *HTTPBuilder) serializeBody() ([]byte, error) {
 		return nil, nil
 	}
 
-	// If it's already a string, return as-is
-	if str, ok := h.body.(string); ok {
-		return []byte(str), nil
+	// Strings and raw bytes are sent as-is
+	switch body := h.body.(type) {
+	case string:
+		return []byte(body), nil
+	case []byte:
+		return body, nil
 	}
 
 	// Otherwise, marshal as JSON
@@ -385,18 +674,26 @@ func (h *HTTPBuilder) serializeBody() ([]byte, error) {
 	return bytes, nil
 }
 
-// readResponseBody reads the response body and caches it for reuse.
+// readResponseBody streams the response body to the end and caches it for
+// reuse. Only the first captureLimit bytes are kept; the length and
+// checksums cover the whole body.
 func (h *HTTPBuilder) readResponseBody() []byte {
-	if h.responseBody != nil {
+	if h.bodyStats != nil {
 		return h.responseBody
 	}
 
-	body, err := io.ReadAll(h.resp.Body)
+	r, err := h.responseReader()
+	if err != nil {
+		h.suite.t.Fatalf("Failed to read response body: %v", err)
+	}
+
+	body, stats, err := captureBody(r, h.captureLimit(), h.checksums)
 	if err != nil {
 		h.suite.t.Fatalf("Failed to read response body: %v", err)
 	}
 
 	h.responseBody = body
+	h.bodyStats = stats
 
 	return body
 }
@@ -418,17 +715,52 @@ func (h *HTTPBuilder) formatError(assertion, expected, actual string) string {
 		sb.WriteString(fmt.Sprintf("Body:     %s\n", h.truncateBody(h.requestBody)))
 	}
 
+	// Streamed bodies only keep a prefix of what was sent
+	if h.sent != nil && h.sent.written > 0 {
+		sb.WriteString(fmt.Sprintf("Body:     %s\n", h.truncateBody(h.sent.data)))
+		sb.WriteString(fmt.Sprintf("Sent:     %d bytes streamed\n", h.sent.written))
+	}
+
+	if h.encodedBody != nil {
+		sb.WriteString(fmt.Sprintf("Encoding: %s, %d bytes sent\n", h.compression, len(h.encodedBody)))
+	}
+
 	sb.WriteString("\n")
 	sb.WriteString(fmt.Sprintf("Response: %d %s\n", h.resp.StatusCode, http.StatusText(h.resp.StatusCode)))
+	sb.WriteString(fmt.Sprintf("Protocol: %s\n", h.resp.Proto))
+
+	if h.resp.TLS != nil {
+		sb.WriteString(fmt.Sprintf("TLS:      %s\n", describeTLS(h.resp.TLS)))
+	}
 
 	h.writeHeaders(&sb, "Response", h.resp.Header)
 
-	// Response body
-	respBody := h.readResponseBody()
-	if len(respBody) > 0 {
+	// Response body; event streams never end, so their parsed events are shown instead
+	if h.sse != nil {
+		sb.WriteString(h.sse.formatEvents())
+	} else if respBody := h.readResponseBody(); len(respBody) > 0 {
 		sb.WriteString(fmt.Sprintf("Body:     %s\n", h.truncateBody(respBody)))
+
+		if h.bodyStats.truncated {
+			sb.WriteString(fmt.Sprintf("Size:     %d bytes, %d captured\n", h.bodyStats.length, len(respBody)))
+		}
+
+		if h.graphql {
+			sb.WriteString(formatGraphQLErrors(respBody))
+		}
+	}
+
+	for key, values := range h.resp.Trailer {
+		// Declared trailers the server never sent have no values.
+		if len(values) > 0 {
+			sb.WriteString(fmt.Sprintf("Trailer:  %s: %s\n", key, strings.Join(values, ", ")))
+		}
 	}
 
+	sb.WriteString(h.formatRedirects())
+	sb.WriteString(h.formatAttempts())
+	sb.WriteString(h.formatReproduce())
+
 	sb.WriteString("\n")
 	sb.WriteString(fmt.Sprintf("%s:\n", assertion))
 	sb.WriteString(fmt.Sprintf("Expected: %s\n", expected))
@@ -439,19 +771,24 @@ func (h *HTTPBuilder) formatError(assertion, expected, actual string) string {
 
 // writeHeaders writes headers to the buffer with proper formatting.
 func (h *HTTPBuilder) writeHeaders(sb *bytes.Buffer, _ string, headers http.Header) {
+	writeHeaderLines(sb, headers)
+}
+
+// writeHeaderLines writes headers in the layout shared by all error reports.
+func writeHeaderLines(w io.Writer, headers http.Header) {
 	if len(headers) == 0 {
 		return
 	}
 
-	sb.WriteString("Headers:  ")
+	_, _ = io.WriteString(w, "Headers:  ")
 
 	first := true
 	for key, values := range headers {
 		if !first {
-			sb.WriteString("          ")
+			_, _ = io.WriteString(w, "          ")
 		}
 
-		fmt.Fprintf(sb, "%s: %s\n", key, values[0])
+		fmt.Fprintf(w, "%s: %s\n", key, values[0])
 
 		first = false
 	}
@@ -459,9 +796,14 @@ func (h *HTTPBuilder) writeHeaders(sb *bytes.Buffer, _ string, headers http.Head
 
 // truncateBody truncates a body if it exceeds the maximum size.
 func (h *HTTPBuilder) truncateBody(body []byte) string {
-	if len(body) <= maxBodySize {
-		return string(body)
+	return truncateString(string(body))
+}
+
+// truncateString truncates s if it exceeds the maximum body size.
+func truncateString(s string) string {
+	if len(s) <= maxBodySize {
+		return s
 	}
 
-	return string(body[:maxBodySize]) + "... (truncated)"
+	return s[:maxBodySize] + "... (truncated)"
 }
@@ -1,10 +1,14 @@
 package e2e
 
 import (
+	"bytes"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 // TestNew verifies the test suite initialization.
@@ -61,3 +65,36 @@ func TestBasicIntegration(t *testing.T) {
 	client := New(t, Config{BaseURL: server.URL})
 	client.GET("/test").Execute(t.Context()).ExpectStatus(200)
 }
+
+// TestShellQuote verifies quoting of text and binary arguments.
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"plain":         `'plain'`,
+		"it's":          `'it'\''s'`,
+		"line\nbreak":   "'line\nbreak'",
+		"\x00\xff'\\ok": `$'\x00\xff\'\\ok'`,
+		"bell\a":        `$'bell\x07'`,
+	}
+
+	for input, want := range tests {
+		if got := shellQuote(input); got != want {
+			t.Errorf("shellQuote(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+// TestWSMessageString verifies that truncated binary messages keep the
+// marker readable.
+func TestWSMessageString(t *testing.T) {
+	msg := wsMessage{messageType: websocket.BinaryMessage, data: bytes.Repeat([]byte{0xab}, maxBodySize+1)}
+
+	want := "binary: 1025 bytes " + strings.Repeat("ab", maxBodySize) + "... (truncated)"
+	if got := msg.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	msg = wsMessage{messageType: websocket.BinaryMessage, data: []byte{0x00, 0xff}}
+	if got := msg.String(); got != "binary: 2 bytes 00ff" {
+		t.Errorf("String() = %q, want %q", got, "binary: 2 bytes 00ff")
+	}
+}
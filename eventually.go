@@ -0,0 +1,113 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// eventually polls a request until its chained assertions pass.
+type eventually struct {
+	timeout  time.Duration
+	interval time.Duration
+
+	// execute re-sends the request; sleep waits for the poll interval.
+	// Both are bound to the context passed to Execute.
+	execute func() error
+	sleep   func() error
+
+	checks   []func() error
+	attempts int
+	lastErr  error
+}
+
+// Eventually makes the request poll until every assertion chained after
+// Execute passes, re-executing it every interval for up to timeout.
+//
+//	client.GET("/jobs/42").
+//		Eventually(30*time.Second, time.Second).
+//		Execute(ctx).
+//		ExpectStatus(200).
+//		ExpectJSON(`{"status":"done"}`)
+func (h *HTTPBuilder) Eventually(timeout, interval time.Duration) *HTTPBuilder {
+	h.eventually = &eventually{
+		timeout:  timeout,
+		interval: interval,
+	}
+
+	return h
+}
+
+// start binds the poller to ctx and performs the first execution, polling
+// until the request itself succeeds.
+func (e *eventually) start(ctx context.Context, h *HTTPBuilder) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	h.suite.t.Cleanup(cancel)
+
+	e.execute = func() error {
+		err := h.execute(ctx)
+		if err != nil && ctx.Err() != nil && e.lastErr != nil {
+			// Keep the last real failure rather than the poller's own deadline.
+			return e.lastErr
+		}
+
+		return err
+	}
+	e.sleep = func() error {
+		return sleepContext(ctx, e.interval)
+	}
+
+	e.attempts = 1
+	e.lastErr = e.execute()
+
+	e.await(h, nil)
+}
+
+// await adds check to the assertions and polls until they all pass.
+func (e *eventually) await(h *HTTPBuilder, check func() error) {
+	if e.execute == nil {
+		h.suite.t.Fatal("Request not executed. Call Execute() first.")
+	}
+
+	if check != nil {
+		e.checks = append(e.checks, check)
+	}
+
+	if e.lastErr == nil {
+		e.lastErr = e.runChecks()
+	}
+
+	for e.lastErr != nil {
+		if err := e.sleep(); err != nil {
+			h.fail(e.failure(err))
+		}
+
+		e.attempts++
+
+		e.lastErr = e.execute()
+		if e.lastErr == nil {
+			e.lastErr = e.runChecks()
+		}
+	}
+}
+
+// runChecks runs every registered assertion, returning the first failure.
+func (e *eventually) runChecks() error {
+	for _, check := range e.checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// failure describes why polling stopped along with the last failing assertion.
+func (e *eventually) failure(reason error) string {
+	if errors.Is(reason, context.DeadlineExceeded) {
+		reason = fmt.Errorf("timed out after %s", e.timeout)
+	}
+
+	return fmt.Sprintf("Eventually: condition not met after %d attempts (%v)\n%s", e.attempts, reason, e.lastErr)
+}
@@ -0,0 +1,329 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultGraphQLPath is the endpoint used when Config.GraphQLPath is empty.
+const defaultGraphQLPath = "/graphql"
+
+// GraphQLBuilder builds a GraphQL operation sent as a standard POST request.
+type GraphQLBuilder struct {
+	http          *HTTPBuilder
+	query         string
+	variables     interface{}
+	operationName string
+}
+
+// graphQLResponse is the standard GraphQL response envelope.
+type graphQLResponse struct {
+	Data   interface{}    `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// graphQLError is a single entry of the errors array.
+type graphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+// GraphQL creates a builder for a GraphQL operation posted to
+// Config.GraphQLPath.
+//
+//	client.GraphQL(`query($id: ID!) { user(id: $id) { name } }`).
+//		Variables(map[string]any{"id": "1"}).
+//		Execute(ctx).
+//		ExpectNoGraphQLErrors().
+//		ExpectData("user.name", "Alice")
+func (s *TestSuite) GraphQL(query string) *GraphQLBuilder {
+	path := s.config.GraphQLPath
+	if path == "" {
+		path = defaultGraphQLPath
+	}
+
+	h := s.POST(path)
+	h.graphql = true
+
+	return &GraphQLBuilder{
+		http:  h,
+		query: query,
+	}
+}
+
+// Variables sets the operation variables.
+func (g *GraphQLBuilder) Variables(variables interface{}) *GraphQLBuilder {
+	g.variables = variables
+
+	return g
+}
+
+// OperationName selects the operation to run when the document has several.
+func (g *GraphQLBuilder) OperationName(name string) *GraphQLBuilder {
+	g.operationName = name
+
+	return g
+}
+
+// Header sets a request header.
+func (g *GraphQLBuilder) Header(key, value string) *GraphQLBuilder {
+	g.http.Header(key, value)
+
+	return g
+}
+
+// Authorization sets the Authorization header.
+func (g *GraphQLBuilder) Authorization(value string) *GraphQLBuilder {
+	g.http.Authorization(value)
+
+	return g
+}
+
+// Timeout sets the request timeout, overriding Config.Timeout.
+func (g *GraphQLBuilder) Timeout(timeout time.Duration) *GraphQLBuilder {
+	g.http.Timeout(timeout)
+
+	return g
+}
+
+// Eventually polls the operation until every assertion chained after
+// Execute passes, as HTTPBuilder.Eventually does.
+func (g *GraphQLBuilder) Eventually(timeout, interval time.Duration) *GraphQLBuilder {
+	g.http.Eventually(timeout, interval)
+
+	return g
+}
+
+// HTTP returns the underlying HTTP request builder.
+func (g *GraphQLBuilder) HTTP() *HTTPBuilder {
+	return g.http
+}
+
+// Execute posts the operation envelope.
+func (g *GraphQLBuilder) Execute(ctx context.Context) *GraphQLBuilder {
+	envelope := map[string]interface{}{"query": g.query}
+
+	if g.variables != nil {
+		envelope["variables"] = g.variables
+	}
+
+	if g.operationName != "" {
+		envelope["operationName"] = g.operationName
+	}
+
+	g.http.Body(envelope).Execute(ctx)
+
+	return g
+}
+
+// ExpectStatus validates the HTTP response status code.
+func (g *GraphQLBuilder) ExpectStatus(statusCode int) *GraphQLBuilder {
+	g.http.ExpectStatus(statusCode)
+
+	return g
+}
+
+// ExpectHeader validates a response header.
+func (g *GraphQLBuilder) ExpectHeader(key, value string) *GraphQLBuilder {
+	g.http.ExpectHeader(key, value)
+
+	return g
+}
+
+// ExpectNoGraphQLErrors validates that the response has no errors array,
+// which servers return with 200 OK when resolvers fail.
+func (g *GraphQLBuilder) ExpectNoGraphQLErrors() *GraphQLBuilder {
+	h := g.http
+
+	h.expect(func() error {
+		resp, err := g.response()
+		if err != nil {
+			return err
+		}
+
+		if len(resp.Errors) == 0 {
+			return nil
+		}
+
+		actual := fmt.Sprintf("%d errors, first: %s", len(resp.Errors), resp.Errors[0].Message)
+
+		return errors.New(h.formatError("GraphQL errors present", "no errors", actual))
+	})
+
+	return g
+}
+
+// ExpectGraphQLError validates that some error has extensions.code equal
+// to code and a path equal to path in dot notation, such as "user.posts.0".
+// An empty code or path matches any.
+func (g *GraphQLBuilder) ExpectGraphQLError(code, path string) *GraphQLBuilder {
+	h := g.http
+
+	h.expect(func() error {
+		resp, err := g.response()
+		if err != nil {
+			return err
+		}
+
+		for _, gqlErr := range resp.Errors {
+			if (code == "" || gqlErr.code() == code) && (path == "" || gqlErr.path() == path) {
+				return nil
+			}
+		}
+
+		expected := fmt.Sprintf("error with code=%q path=%q", code, path)
+
+		return errors.New(h.formatError("GraphQL error not found", expected, fmt.Sprintf("%d errors", len(resp.Errors))))
+	})
+
+	return g
+}
+
+// ExpectData validates the value at path within data, in dot notation with
+// numeric array indexes such as "users.0.name". An empty path selects the
+// whole data object. Values are compared as JSON like ExpectJSON, except
+// that a string which is not valid JSON is compared as a string value.
+func (g *GraphQLBuilder) ExpectData(path string, expected interface{}) *GraphQLBuilder {
+	h := g.http
+
+	h.expect(func() error {
+		resp, err := g.response()
+		if err != nil {
+			return err
+		}
+
+		normalized, err := normalizeJSON(expected)
+		if str, ok := expected.(string); ok && err != nil {
+			normalized, err = str, nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		expectedJSON, _ := json.Marshal(normalized)
+		assertion := fmt.Sprintf("Data mismatch (%s)", path)
+
+		actual, ok := lookupPath(resp.Data, path)
+		if !ok {
+			return errors.New(h.formatError(assertion, string(expectedJSON), "<missing>"))
+		}
+
+		if !jsonEqual(normalized, actual) {
+			actualJSON, _ := json.Marshal(actual)
+
+			return errors.New(h.formatError(assertion, string(expectedJSON), string(actualJSON)))
+		}
+
+		return nil
+	})
+
+	return g
+}
+
+// response decodes the GraphQL response envelope.
+func (g *GraphQLBuilder) response() (*graphQLResponse, error) {
+	body, err := g.http.completeBody()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := parseGraphQLResponse(body)
+	if err != nil {
+		return nil, errors.New(g.http.formatError("Invalid GraphQL response", "JSON response envelope", err.Error()))
+	}
+
+	return resp, nil
+}
+
+// parseGraphQLResponse decodes body as a GraphQL response envelope.
+func parseGraphQLResponse(body []byte) (*graphQLResponse, error) {
+	var resp graphQLResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// lookupPath walks a decoded JSON value along a dot-separated path.
+func lookupPath(value interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return value, true
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[segment]
+			if !ok {
+				return nil, false
+			}
+
+			value = next
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+
+			value = v[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// code returns extensions.code, if any.
+func (e graphQLError) code() string {
+	code, _ := e.Extensions["code"].(string)
+
+	return code
+}
+
+// path returns the error path in dot notation.
+func (e graphQLError) path() string {
+	segments := make([]string, 0, len(e.Path))
+	for _, segment := range e.Path {
+		segments = append(segments, fmt.Sprint(segment))
+	}
+
+	return strings.Join(segments, ".")
+}
+
+// formatGraphQLErrors summarizes the errors array of a GraphQL response
+// body for error reports.
+func formatGraphQLErrors(body []byte) string {
+	resp, err := parseGraphQLResponse(body)
+	if err != nil || len(resp.Errors) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("GraphQL Errors:\n")
+
+	for i, gqlErr := range resp.Errors {
+		fmt.Fprintf(&sb, "  #%d ", i+1)
+
+		if code := gqlErr.code(); code != "" {
+			fmt.Fprintf(&sb, "[%s] ", code)
+		}
+
+		if path := gqlErr.path(); path != "" {
+			fmt.Fprintf(&sb, "%s: ", path)
+		}
+
+		sb.WriteString(gqlErr.Message + "\n")
+	}
+
+	return sb.String()
+}
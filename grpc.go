@@ -0,0 +1,441 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// GRPCConfig configures gRPC requests made through the suite.
+type GRPCConfig struct {
+	// DescriptorSets lists files holding serialized FileDescriptorSets, as
+	// written by protoc --descriptor_set_out --include_imports. They are
+	// used to call services without generated code.
+	DescriptorSets []string
+	// DisableReflection stops methods missing from the descriptor sets and
+	// the generated code registry from being resolved via server reflection.
+	DisableReflection bool
+	// DialOptions are added to the options used to connect.
+	DialOptions []grpc.DialOption
+}
+
+// GRPCBuilder builds and asserts on a unary gRPC or gRPC-Web call.
+type GRPCBuilder struct {
+	suite    *TestSuite
+	service  string
+	method   string
+	message  interface{}
+	metadata metadata.MD
+	timeout  time.Duration
+	web      bool
+
+	// Call details for assertions and error reporting
+	target      string
+	requestJSON []byte
+	response    proto.Message
+	status      *status.Status
+	header      metadata.MD
+	trailer     metadata.MD
+	executed    bool
+}
+
+// GRPC creates a builder for a unary call to method of the fully qualified
+// service, such as GRPC("grpc.health.v1.Health", "Check"). The connection
+// targets the host of BaseURL and uses TLS when its scheme is https.
+func (s *TestSuite) GRPC(service, method string) *GRPCBuilder {
+	return &GRPCBuilder{
+		suite:   s,
+		service: service,
+		method:  method,
+	}
+}
+
+// Message sets the request message. It accepts a proto.Message, a JSON
+// string in protojson format, or any value that marshals to such JSON.
+func (g *GRPCBuilder) Message(message interface{}) *GRPCBuilder {
+	g.message = message
+
+	return g
+}
+
+// Metadata adds a request metadata entry.
+func (g *GRPCBuilder) Metadata(key, value string) *GRPCBuilder {
+	if g.metadata == nil {
+		g.metadata = metadata.MD{}
+	}
+
+	g.metadata.Append(key, value)
+
+	return g
+}
+
+// Authorization sets the authorization metadata entry.
+func (g *GRPCBuilder) Authorization(value string) *GRPCBuilder {
+	return g.Metadata("authorization", value)
+}
+
+// Timeout sets the call deadline, overriding Config.Timeout.
+func (g *GRPCBuilder) Timeout(timeout time.Duration) *GRPCBuilder {
+	g.timeout = timeout
+
+	return g
+}
+
+// Web sends the call using the gRPC-Web protocol over the suite's HTTP
+// client instead of native gRPC, so it also works in handler mode.
+func (g *GRPCBuilder) Web() *GRPCBuilder {
+	g.web = true
+
+	return g
+}
+
+// Execute performs the call. A non-OK status does not fail the test by
+// itself; assert it with ExpectCode.
+func (g *GRPCBuilder) Execute(ctx context.Context) *GRPCBuilder {
+	methodDesc, err := g.suite.grpcMethod(ctx, g.service, g.method)
+	if err != nil {
+		g.suite.t.Fatalf("Failed to resolve gRPC method %s/%s: %v", g.service, g.method, err)
+	}
+
+	request, err := g.buildRequest(methodDesc)
+	if err != nil {
+		g.suite.t.Fatalf("Failed to build gRPC request: %v", err)
+	}
+
+	timeout := g.timeout
+	if timeout <= 0 {
+		timeout = g.suite.config.Timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	response := dynamicpb.NewMessage(methodDesc.Output())
+
+	if g.web {
+		err = g.invokeWeb(ctx, request, response)
+	} else {
+		err = g.invoke(ctx, request, response)
+	}
+
+	if err != nil {
+		g.suite.t.Fatalf("Failed to execute gRPC request %s/%s: %v", g.service, g.method, err)
+	}
+
+	g.response = response
+	g.executed = true
+
+	return g
+}
+
+// buildRequest converts the configured message into the method's input type.
+func (g *GRPCBuilder) buildRequest(methodDesc protoreflect.MethodDescriptor) (proto.Message, error) {
+	input := methodDesc.Input()
+
+	if msg, ok := g.message.(proto.Message); ok {
+		if name := msg.ProtoReflect().Descriptor().FullName(); name != input.FullName() {
+			return nil, fmt.Errorf("message type %s does not match method input %s", name, input.FullName())
+		}
+
+		data, err := protojson.Marshal(msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal message: %w", err)
+		}
+
+		g.requestJSON = data
+
+		return msg, nil
+	}
+
+	data := []byte("{}")
+
+	if g.message != nil {
+		normalized, err := normalizeJSON(g.message)
+		if err != nil {
+			return nil, err
+		}
+
+		data, _ = json.Marshal(normalized)
+	}
+
+	g.requestJSON = data
+	request := dynamicpb.NewMessage(input)
+
+	if err := protojson.Unmarshal(data, request); err != nil {
+		return nil, fmt.Errorf("failed to decode message as %s: %w", input.FullName(), err)
+	}
+
+	return request, nil
+}
+
+// invoke performs a native gRPC call.
+func (g *GRPCBuilder) invoke(ctx context.Context, request, response proto.Message) error {
+	conn, err := g.suite.grpcConn()
+	if err != nil {
+		return err
+	}
+
+	g.target = conn.Target()
+
+	if len(g.metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, g.metadata)
+	}
+
+	err = conn.Invoke(ctx, "/"+g.service+"/"+g.method, request, response, grpc.Header(&g.header), grpc.Trailer(&g.trailer))
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("unexpected error: %w", err)
+	}
+
+	g.status = st
+
+	return nil
+}
+
+// grpcConn returns the suite's gRPC connection, creating it on first use.
+func (s *TestSuite) grpcConn() (*grpc.ClientConn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.grpcClient != nil {
+		return s.grpcClient, nil
+	}
+
+	if s.config.Handler != nil {
+		return nil, errors.New("native gRPC is not supported in handler mode; use Web()")
+	}
+
+	baseURL, err := url.Parse(s.config.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base URL: %w", err)
+	}
+
+	options, err := s.grpcDialOptions(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient("passthrough:///"+hostPort(baseURL), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+
+	s.t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	s.grpcClient = conn
+
+	return conn, nil
+}
+
+// grpcDialOptions derives credentials and the dialer from the suite config.
+func (s *TestSuite) grpcDialOptions(baseURL *url.URL) ([]grpc.DialOption, error) {
+	creds := insecure.NewCredentials()
+
+	if baseURL.Scheme == "https" {
+		tlsConfig := new(TLSConfig)
+		if s.config.TLS != nil {
+			tlsConfig = s.config.TLS
+		}
+
+		clientConfig, err := tlsConfig.clientConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		creds = credentials.NewTLS(clientConfig)
+	}
+
+	options := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+
+	dial, err := dialer(s.config)
+	if err != nil {
+		return nil, err
+	}
+
+	if dial != nil {
+		options = append(options, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return dial(ctx, "tcp", addr)
+		}))
+	}
+
+	if s.config.GRPC != nil {
+		options = append(options, s.config.GRPC.DialOptions...)
+	}
+
+	return options, nil
+}
+
+// hostPort returns the host of u with its scheme's default port applied.
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+
+	if u.Scheme == "https" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	return net.JoinHostPort(u.Hostname(), "80")
+}
+
+// requireExecuted fails the test when Execute has not been called.
+func (g *GRPCBuilder) requireExecuted() {
+	if !g.executed {
+		g.suite.t.Fatal("Request not executed. Call Execute() first.")
+	}
+}
+
+// Response returns the decoded response message.
+func (g *GRPCBuilder) Response() proto.Message {
+	g.requireExecuted()
+
+	return g.response
+}
+
+// ExpectCode validates the call's status code.
+func (g *GRPCBuilder) ExpectCode(code codes.Code) *GRPCBuilder {
+	g.requireExecuted()
+
+	if g.status.Code() != code {
+		actual := fmt.Sprintf("%s: %s", g.status.Code(), g.status.Message())
+		g.suite.t.Fatal(g.formatError("Status code mismatch", code.String(), actual))
+	}
+
+	return g
+}
+
+// ExpectMessage validates the response message rendered as protojson
+// against expected, using the same matching as ExpectJSON.
+func (g *GRPCBuilder) ExpectMessage(expected interface{}) *GRPCBuilder {
+	g.requireExecuted()
+
+	normalized, err := normalizeJSON(expected)
+	if err != nil {
+		g.suite.t.Fatal(err.Error())
+	}
+
+	var actual interface{}
+	if err := json.Unmarshal(g.responseJSON(), &actual); err != nil {
+		g.suite.t.Fatalf("Failed to parse response message: %v", err)
+	}
+
+	if !jsonEqual(normalized, actual) {
+		expectedJSON, _ := json.MarshalIndent(normalized, "", "  ")
+		actualJSON, _ := json.MarshalIndent(actual, "", "  ")
+		g.suite.t.Fatal(g.formatError("Message mismatch", string(expectedJSON), string(actualJSON)))
+	}
+
+	return g
+}
+
+// ExpectHeader validates a response header metadata entry.
+func (g *GRPCBuilder) ExpectHeader(key, value string) *GRPCBuilder {
+	g.requireExecuted()
+
+	return g.expectMetadata("Header", g.header, key, value)
+}
+
+// ExpectTrailer validates a response trailer metadata entry.
+func (g *GRPCBuilder) ExpectTrailer(key, value string) *GRPCBuilder {
+	g.requireExecuted()
+
+	return g.expectMetadata("Trailer", g.trailer, key, value)
+}
+
+// expectMetadata validates the first value of key in md.
+func (g *GRPCBuilder) expectMetadata(kind string, md metadata.MD, key, value string) *GRPCBuilder {
+	var actual string
+	if values := md.Get(key); len(values) > 0 {
+		actual = values[0]
+	}
+
+	if actual != value {
+		assertion := fmt.Sprintf("%s mismatch (%s)", kind, key)
+		g.suite.t.Fatal(g.formatError(assertion, value, actual))
+	}
+
+	return g
+}
+
+// responseJSON renders the response message as protojson.
+func (g *GRPCBuilder) responseJSON() []byte {
+	data, err := protojson.Marshal(g.response)
+	if err != nil {
+		return []byte("{}")
+	}
+
+	return data
+}
+
+// formatError creates a detailed error message with call information.
+func (g *GRPCBuilder) formatError(assertion, expected, actual string) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n=== gRPC Request Failed ===\n")
+	fmt.Fprintf(&sb, "Request:  %s/%s\n", g.service, g.method)
+	fmt.Fprintf(&sb, "Target:   %s\n", g.target)
+	writeMetadata(&sb, "Metadata:", g.metadata)
+	fmt.Fprintf(&sb, "Message:  %s\n", truncateString(string(g.requestJSON)))
+
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "Status:   %s", g.status.Code())
+
+	if msg := g.status.Message(); msg != "" {
+		fmt.Fprintf(&sb, " (%s)", msg)
+	}
+
+	sb.WriteString("\n")
+	writeMetadata(&sb, "Headers: ", g.header)
+	fmt.Fprintf(&sb, "Response: %s\n", truncateString(string(g.responseJSON())))
+	writeMetadata(&sb, "Trailers:", g.trailer)
+
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "%s:\n", assertion)
+	fmt.Fprintf(&sb, "Expected: %s\n", expected)
+	fmt.Fprintf(&sb, "Actual:   %s\n", actual)
+
+	return sb.String()
+}
+
+// writeMetadata writes metadata entries in sorted order.
+func writeMetadata(sb *strings.Builder, label string, md metadata.MD) {
+	if len(md) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(md))
+	for key := range md {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		prefix := "          "
+		if i == 0 {
+			prefix = label + " "
+		}
+
+		fmt.Fprintf(sb, "%s%s: %s\n", prefix, key, strings.Join(md[key], ", "))
+	}
+}
@@ -0,0 +1,204 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// grpcMethod resolves a method descriptor, looking in the configured
+// descriptor sets, then the generated code registry, then server reflection.
+func (s *TestSuite) grpcMethod(ctx context.Context, service, method string) (protoreflect.MethodDescriptor, error) {
+	files, err := s.descriptorFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, registry := range []*protoregistry.Files{files, protoregistry.GlobalFiles} {
+		if md, ok := findMethod(registry, service, method); ok {
+			return md, nil
+		}
+	}
+
+	if s.config.GRPC != nil && s.config.GRPC.DisableReflection {
+		return nil, fmt.Errorf("service %s not found in descriptor sets or generated code", service)
+	}
+
+	files, err = s.reflectFiles(ctx, service)
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found locally and reflection failed: %w", service, err)
+	}
+
+	if md, ok := findMethod(files, service, method); ok {
+		return md, nil
+	}
+
+	return nil, fmt.Errorf("method %s not found in service %s", method, service)
+}
+
+// findMethod looks up service/method in files.
+func findMethod(files *protoregistry.Files, service, method string) (protoreflect.MethodDescriptor, bool) {
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, false
+	}
+
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, false
+	}
+
+	md := sd.Methods().ByName(protoreflect.Name(method))
+
+	return md, md != nil
+}
+
+// descriptorFiles loads Config.GRPC.DescriptorSets once.
+func (s *TestSuite) descriptorFiles() (*protoregistry.Files, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.grpcFiles != nil {
+		return s.grpcFiles, nil
+	}
+
+	set := new(descriptorpb.FileDescriptorSet)
+
+	if s.config.GRPC != nil {
+		for _, path := range s.config.GRPC.DescriptorSets {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read descriptor set: %w", err)
+			}
+
+			var fileSet descriptorpb.FileDescriptorSet
+			if err := proto.Unmarshal(data, &fileSet); err != nil {
+				return nil, fmt.Errorf("failed to parse descriptor set %s: %w", path, err)
+			}
+
+			set.File = append(set.File, fileSet.GetFile()...)
+		}
+	}
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build descriptor set: %w", err)
+	}
+
+	s.grpcFiles = files
+
+	return files, nil
+}
+
+// reflectFiles fetches the file defining symbol and its dependencies via
+// server reflection. Dependencies the server does not return are taken
+// from the generated code registry.
+func (s *TestSuite) reflectFiles(ctx context.Context, symbol string) (*protoregistry.Files, error) {
+	conn, err := s.grpcConn()
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reflection stream: %w", err)
+	}
+
+	defer func() {
+		_ = stream.CloseSend()
+	}()
+
+	fetched := make(map[string]*descriptorpb.FileDescriptorProto)
+
+	pending, err := reflectFile(stream, &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+	}, fetched)
+	if err != nil {
+		return nil, err
+	}
+
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+
+		if _, ok := fetched[name]; ok {
+			continue
+		}
+
+		if fd, err := protoregistry.GlobalFiles.FindFileByPath(name); err == nil {
+			fetched[name] = protodesc.ToFileDescriptorProto(fd)
+			pending = append(pending, fetched[name].GetDependency()...)
+
+			continue
+		}
+
+		deps, err := reflectFile(stream, &reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+		}, fetched)
+		if err != nil {
+			return nil, err
+		}
+
+		pending = append(pending, deps...)
+	}
+
+	return newFiles(fetched)
+}
+
+// newFiles builds a registry from the fetched files.
+func newFiles(fetched map[string]*descriptorpb.FileDescriptorProto) (*protoregistry.Files, error) {
+	set := new(descriptorpb.FileDescriptorSet)
+	for _, fdp := range fetched {
+		set.File = append(set.File, fdp)
+	}
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build reflected descriptors: %w", err)
+	}
+
+	return files, nil
+}
+
+// reflectFile sends one reflection request, stores the returned files in
+// fetched and returns their dependencies.
+func reflectFile(
+	stream reflectionpb.ServerReflection_ServerReflectionInfoClient,
+	request *reflectionpb.ServerReflectionRequest,
+	fetched map[string]*descriptorpb.FileDescriptorProto,
+) ([]string, error) {
+	if err := stream.Send(request); err != nil {
+		return nil, fmt.Errorf("failed to send reflection request: %w", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive reflection response: %w", err)
+	}
+
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, errors.New(errResp.GetErrorMessage())
+	}
+
+	var deps []string
+
+	for _, data := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fdp := new(descriptorpb.FileDescriptorProto)
+		if err := proto.Unmarshal(data, fdp); err != nil {
+			return nil, fmt.Errorf("failed to parse reflected descriptor: %w", err)
+		}
+
+		fetched[fdp.GetName()] = fdp
+		deps = append(deps, fdp.GetDependency()...)
+	}
+
+	return deps, nil
+}
@@ -0,0 +1,191 @@
+package e2e
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// gRPC-Web frame flags.
+const (
+	grpcWebDataFrame    = 0x00
+	grpcWebTrailerFrame = 0x80
+)
+
+// invokeWeb performs a gRPC-Web call using the suite's HTTP client.
+func (g *GRPCBuilder) invokeWeb(ctx context.Context, request, response proto.Message) error {
+	reqURL, err := g.suite.resolveURL("/"+g.service+"/"+g.method, nil)
+	if err != nil {
+		return err
+	}
+
+	g.target = reqURL.String()
+
+	payload, err := proto.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.target, bytes.NewReader(grpcWebFrame(grpcWebDataFrame, payload)))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	req.Header.Set("X-Grpc-Web", "1")
+
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set("Grpc-Timeout", strconv.FormatInt(time.Until(deadline).Milliseconds(), 10)+"m")
+	}
+
+	for key, values := range g.metadata {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := g.suite.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send gRPC-Web request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return g.readWebResponse(resp, response)
+}
+
+// readWebResponse decodes the frames of a gRPC-Web response. The status is
+// taken from the trailer frame, or from the headers for trailers-only
+// responses.
+func (g *GRPCBuilder) readWebResponse(resp *http.Response, response proto.Message) error {
+	g.header = headerMetadata(resp.Header)
+	g.trailer = metadata.MD{}
+
+	if resp.StatusCode != http.StatusOK {
+		g.status = status.Newf(codes.Unknown, "unexpected HTTP status %s", resp.Status)
+
+		return nil
+	}
+
+	reader := bufio.NewReader(resp.Body)
+
+	for {
+		flag, payload, err := readGRPCWebFrame(reader)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if flag&grpcWebTrailerFrame != 0 {
+			g.trailer = parseWebTrailer(payload)
+
+			continue
+		}
+
+		if err := proto.Unmarshal(payload, response); err != nil {
+			return fmt.Errorf("failed to decode response message: %w", err)
+		}
+	}
+
+	statusMD := g.trailer
+	if len(statusMD.Get("grpc-status")) == 0 {
+		statusMD = g.header
+	}
+
+	g.status = webStatus(statusMD)
+
+	return nil
+}
+
+// grpcWebFrame encodes a length-prefixed frame.
+func grpcWebFrame(flag byte, payload []byte) []byte {
+	frame := make([]byte, 5+len(payload))
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(payload))) //nolint:gosec // Messages are far below 4 GiB.
+	copy(frame[5:], payload)
+
+	return frame
+}
+
+// readGRPCWebFrame reads one length-prefixed frame. It returns io.EOF when
+// no frame remains.
+func readGRPCWebFrame(r io.Reader) (byte, []byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, nil, io.EOF
+		}
+
+		return 0, nil, fmt.Errorf("failed to read frame header: %w", err)
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(prefix[1:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, fmt.Errorf("failed to read frame: %w", err)
+	}
+
+	return prefix[0], payload, nil
+}
+
+// parseWebTrailer decodes a trailer frame of "key: value" lines.
+func parseWebTrailer(payload []byte) metadata.MD {
+	md := metadata.MD{}
+
+	for _, line := range strings.Split(string(payload), "\r\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		md.Append(strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value))
+	}
+
+	return md
+}
+
+// headerMetadata converts response headers into metadata with lowercase keys.
+func headerMetadata(header http.Header) metadata.MD {
+	md := metadata.MD{}
+
+	for key, values := range header {
+		md.Append(strings.ToLower(key), values...)
+	}
+
+	return md
+}
+
+// webStatus builds the call status from grpc-status and grpc-message.
+func webStatus(md metadata.MD) *status.Status {
+	values := md.Get("grpc-status")
+	if len(values) == 0 {
+		return status.New(codes.Unknown, "response has no grpc-status")
+	}
+
+	code, err := strconv.ParseUint(values[0], 10, 32)
+	if err != nil {
+		return status.Newf(codes.Unknown, "invalid grpc-status %q", values[0])
+	}
+
+	var message string
+	if messages := md.Get("grpc-message"); len(messages) > 0 {
+		message, _ = url.PathUnescape(messages[0])
+	}
+
+	return status.New(codes.Code(code), message)
+}
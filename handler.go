@@ -0,0 +1,202 @@
+package e2e
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// defaultHandlerBaseURL is the base URL used in handler mode when none is configured.
+const defaultHandlerBaseURL = "http://example.com"
+
+// NewWithHandler creates a test suite that sends requests directly to handler
+// in-process, without binding a network port.
+func NewWithHandler(tb testing.TB, handler http.Handler) *TestSuite {
+	tb.Helper()
+
+	return New(tb, Config{Handler: handler})
+}
+
+// handlerTransport is an http.RoundTripper that serves requests with an http.Handler.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+// RoundTrip runs the handler in its own goroutine and returns as soon as the
+// response headers are written, so streamed bodies are delivered incrementally.
+func (t *handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	serverReq := newServerRequest(req)
+	pr, pw := io.Pipe()
+	w := &pipeResponseWriter{
+		header:  make(http.Header),
+		trailer: make(http.Header),
+		body:    pw,
+		ready:   make(chan struct{}),
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				w.fail(fmt.Errorf("handler panic: %v", r))
+
+				return
+			}
+
+			w.finish()
+		}()
+
+		t.handler.ServeHTTP(w, serverReq)
+	}()
+
+	select {
+	case <-w.ready:
+	case <-req.Context().Done():
+		_ = pr.CloseWithError(req.Context().Err())
+
+		return nil, fmt.Errorf("handler did not respond: %w", req.Context().Err())
+	}
+
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	return w.response(req, pr), nil
+}
+
+// newServerRequest converts an outgoing client request into the form a handler
+// receives from net/http.
+func newServerRequest(req *http.Request) *http.Request {
+	serverReq := req.Clone(req.Context())
+	serverReq.RequestURI = req.URL.RequestURI()
+	serverReq.RemoteAddr = "192.0.2.1:1234"
+	serverReq.Proto = "HTTP/1.1"
+	serverReq.ProtoMajor = 1
+	serverReq.ProtoMinor = 1
+
+	if serverReq.Host == "" {
+		serverReq.Host = req.URL.Host
+	}
+
+	if serverReq.Body == nil {
+		serverReq.Body = http.NoBody
+	}
+
+	return serverReq
+}
+
+// pipeResponseWriter is an http.ResponseWriter that streams the body through a pipe.
+type pipeResponseWriter struct {
+	header  http.Header
+	trailer http.Header
+	body    *io.PipeWriter
+
+	mu          sync.Mutex
+	status      int
+	wroteHeader bool
+	snapshot    http.Header
+	ready       chan struct{}
+	err         error
+}
+
+// Header returns the response headers.
+func (w *pipeResponseWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records the status code and releases the waiting round trip.
+func (w *pipeResponseWriter) WriteHeader(statusCode int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.wroteHeader {
+		return
+	}
+
+	w.status = statusCode
+	w.wroteHeader = true
+	w.snapshot = w.header.Clone()
+	close(w.ready)
+}
+
+// Write streams body bytes to the client.
+func (w *pipeResponseWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+
+	n, err := w.body.Write(p)
+	if err != nil {
+		return n, fmt.Errorf("client closed response body: %w", err)
+	}
+
+	return n, nil
+}
+
+// Flush is a no-op; writes are delivered to the reader synchronously.
+func (w *pipeResponseWriter) Flush() {
+	w.WriteHeader(http.StatusOK)
+}
+
+// finish completes the response once the handler returns. Trailers are
+// collected before the body is closed so they are visible at EOF.
+func (w *pipeResponseWriter) finish() {
+	w.WriteHeader(http.StatusOK)
+
+	for _, declared := range w.snapshot.Values("Trailer") {
+		for _, key := range strings.Split(declared, ",") {
+			key = http.CanonicalHeaderKey(strings.TrimSpace(key))
+			if values := w.header.Values(key); len(values) > 0 {
+				w.trailer[key] = values
+			}
+		}
+	}
+
+	for key, values := range w.header {
+		if name, ok := strings.CutPrefix(key, http.TrailerPrefix); ok {
+			w.trailer[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+
+	_ = w.body.Close()
+}
+
+// fail aborts the response with err.
+func (w *pipeResponseWriter) fail(err error) {
+	w.mu.Lock()
+	if !w.wroteHeader {
+		w.err = err
+	}
+	w.mu.Unlock()
+
+	w.WriteHeader(http.StatusInternalServerError)
+	_ = w.body.CloseWithError(err)
+}
+
+// response builds the client-side response.
+func (w *pipeResponseWriter) response(req *http.Request, body io.ReadCloser) *http.Response {
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", w.status, http.StatusText(w.status)),
+		StatusCode:    w.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        w.snapshot,
+		Body:          body,
+		Trailer:       w.trailer,
+		ContentLength: -1,
+		Request:       req,
+	}
+
+	if cl, err := strconv.ParseInt(w.snapshot.Get("Content-Length"), 10, 64); err == nil {
+		resp.ContentLength = cl
+	}
+
+	if req.Method == http.MethodHead {
+		_ = body.Close()
+		resp.Body = http.NoBody
+	}
+
+	return resp
+}
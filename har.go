@@ -0,0 +1,419 @@
+package e2e
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// harRecorder collects the exchanges of a suite and writes them as a HAR
+// 1.2 log when the test ends.
+type harRecorder struct {
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+type harLog struct {
+	Log harLogBody `json:"log"`
+}
+
+type harLogBody struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// harTimings are in milliseconds; -1 marks a phase that did not happen.
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harTimer records connection phases of one request through httptrace.
+type harTimer struct {
+	start                    time.Time
+	dnsStart, dnsDone        time.Time
+	connectStart, connectEnd time.Time
+	tlsStart, tlsDone        time.Time
+	wrote, firstByte         time.Time
+	done                     time.Time
+}
+
+// newHARTimer starts timing a request.
+func newHARTimer() *harTimer {
+	return &harTimer{start: time.Now()}
+}
+
+// withTrace attaches the timer's hooks to ctx.
+func (t *harTimer) withTrace(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.dnsStart = time.Now() },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.dnsDone = time.Now() },
+		ConnectStart:         func(string, string) { t.connectStart = time.Now() },
+		ConnectDone:          func(string, string, error) { t.connectEnd = time.Now() },
+		TLSHandshakeStart:    func() { t.tlsStart = time.Now() },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.tlsDone = time.Now() },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.wrote = time.Now() },
+		GotFirstResponseByte: func() { t.firstByte = time.Now() },
+	})
+}
+
+// timings converts the recorded phases into HAR timings.
+func (t *harTimer) timings() harTimings {
+	// Handler mode and reused connections skip phases, so fall back to
+	// the start for missing marks.
+	wrote := orTime(t.wrote, t.start)
+	firstByte := orTime(t.firstByte, wrote)
+
+	return harTimings{
+		Blocked: -1,
+		DNS:     phase(t.dnsStart, t.dnsDone),
+		Connect: phase(t.connectStart, t.connectEnd),
+		SSL:     phase(t.tlsStart, t.tlsDone),
+		Send:    0,
+		Wait:    milliseconds(firstByte.Sub(wrote)),
+		Receive: milliseconds(orTime(t.done, firstByte).Sub(firstByte)),
+	}
+}
+
+func orTime(t, fallback time.Time) time.Time {
+	if t.IsZero() {
+		return fallback
+	}
+
+	return t
+}
+
+func phase(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return -1
+	}
+
+	return milliseconds(end.Sub(start))
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// traceRequest attaches a timer to req when HAR recording is enabled.
+func (h *HTTPBuilder) traceRequest(req *http.Request) (*http.Request, *harTimer) {
+	if h.suite.config.HARDir == "" {
+		return req, nil
+	}
+
+	timer := newHARTimer()
+
+	return req.WithContext(timer.withTrace(req.Context())), timer
+}
+
+// completeRequest stores the final response and records the exchange when
+// HAR recording is enabled.
+func (h *HTTPBuilder) completeRequest(reqURL *url.URL, resp *http.Response, err error, timer *harTimer) error {
+	if err != nil && timer != nil {
+		h.recordFailedHAR(timer, err)
+	}
+
+	if err := h.finishRequest(reqURL, resp, err); err != nil {
+		return err
+	}
+
+	if timer != nil {
+		h.recordHAR(timer)
+	}
+
+	return nil
+}
+
+// recordHAR adds the completed exchange to the suite's HAR log. The body is
+// read up front so it is captured before the response is closed; event
+// streams never end and are recorded without content.
+func (h *HTTPBuilder) recordHAR(timer *harTimer) {
+	mediaType, _, _ := mime.ParseMediaType(h.resp.Header.Get("Content-Type"))
+	streaming := mediaType == "text/event-stream"
+
+	if !streaming {
+		h.readResponseBody()
+	}
+
+	response := h.suite.harResponse(h.resp)
+
+	if streaming {
+		response.Content.Comment = "event stream not recorded"
+	} else {
+		response.setContent(h.responseBody, h.bodyStats.length, h.bodyStats.truncated)
+	}
+
+	h.addHAREntry(timer, h.harRequest(h.resp.Proto), response, "")
+}
+
+// recordRetriedHAR adds an attempt that is about to be retried to the HAR
+// log. Its body is discarded anyway, so only the start of it is kept.
+func (h *HTTPBuilder) recordRetriedHAR(timer *harTimer, resp *http.Response, record attempt) {
+	if timer == nil {
+		return
+	}
+
+	if record.err != nil {
+		h.recordFailedHAR(timer, record.err)
+
+		return
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	length, truncated := int64(len(body)), len(body) > maxBodySize
+
+	if truncated {
+		body, length = body[:maxBodySize], -1
+	}
+
+	response := h.suite.harResponse(resp)
+	response.setContent(body, length, truncated)
+
+	h.addHAREntry(timer, h.harRequest(resp.Proto), response,
+		fmt.Sprintf("attempt %d, retried after %s", record.number, record.wait))
+}
+
+// recordFailedHAR adds an attempt that got no response to the HAR log, with
+// the error as its comment.
+func (h *HTTPBuilder) recordFailedHAR(timer *harTimer, err error) {
+	response := harResponse{
+		Cookies:     []harNameValue{},
+		Headers:     []harNameValue{},
+		Content:     harContent{MimeType: "x-unknown"},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+
+	h.addHAREntry(timer, h.harRequest(""), response, err.Error())
+}
+
+// addHAREntry appends one exchange to the suite's HAR log.
+func (h *HTTPBuilder) addHAREntry(timer *harTimer, req harRequest, resp harResponse, comment string) {
+	recorder := h.suite.harRecorder()
+
+	timer.done = time.Now()
+
+	entry := harEntry{
+		StartedDateTime: timer.start.Format(time.RFC3339Nano),
+		Time:            milliseconds(timer.done.Sub(timer.start)),
+		Request:         req,
+		Response:        resp,
+		Timings:         timer.timings(),
+		Comment:         comment,
+	}
+
+	recorder.mu.Lock()
+	recorder.entries = append(recorder.entries, entry)
+	recorder.mu.Unlock()
+}
+
+// harRequest describes the sent request from the stored request details.
+func (h *HTTPBuilder) harRequest(proto string) harRequest {
+	req := harRequest{
+		Method:      h.method,
+		URL:         h.requestURL,
+		HTTPVersion: proto,
+		Cookies:     []harNameValue{},
+		Headers:     harPairs(h.suite.redactHeaders(h.requestHeaders)),
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    int64(len(h.requestBody)),
+	}
+
+	if u, err := url.Parse(h.requestURL); err == nil {
+		req.QueryString = harPairs(u.Query())
+	}
+
+	body := h.requestBody
+	if h.sent != nil {
+		body = h.sent.data
+		req.BodySize = h.sent.written
+	}
+
+	if h.body != nil {
+		req.PostData = &harPostData{
+			MimeType: h.requestHeaders.Get("Content-Type"),
+			Text:     string(body),
+		}
+	}
+
+	return req
+}
+
+// harResponse describes resp without its content.
+func (s *TestSuite) harResponse(resp *http.Response) harResponse {
+	return harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: resp.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     harPairs(s.redactHeaders(resp.Header)),
+		Content:     harContent{MimeType: resp.Header.Get("Content-Type"), Size: -1},
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+}
+
+// setContent records the captured body of a response that was length bytes
+// long in total.
+func (r *harResponse) setContent(body []byte, length int64, truncated bool) {
+	r.Content.Size = length
+	r.BodySize = length
+
+	if utf8.Valid(body) {
+		r.Content.Text = string(body)
+	} else {
+		r.Content.Text = base64.StdEncoding.EncodeToString(body)
+		r.Content.Encoding = "base64"
+	}
+
+	if truncated {
+		r.Content.Comment = fmt.Sprintf("truncated to the first %d bytes", len(body))
+	}
+}
+
+// harPairs flattens headers or query parameters into name/value pairs
+// sorted by name.
+func harPairs(values map[string][]string) []harNameValue {
+	pairs := []harNameValue{}
+
+	for name, list := range values {
+		for _, value := range list {
+			pairs = append(pairs, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Name < pairs[j].Name })
+
+	return pairs
+}
+
+// harRecorder returns the suite's recorder, registering the cleanup that
+// writes the log on first use.
+func (s *TestSuite) harRecorder() *harRecorder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.har == nil {
+		s.har = new(harRecorder)
+		s.t.Cleanup(s.writeHAR)
+	}
+
+	return s.har
+}
+
+// writeHAR writes the recorded exchanges to Config.HARDir.
+func (s *TestSuite) writeHAR() {
+	s.har.mu.Lock()
+	defer s.har.mu.Unlock()
+
+	log := harLog{Log: harLogBody{
+		Version: "1.2",
+		Creator: harCreator{Name: "github.com/sivchari/e2e", Version: "1.0"},
+		Entries: s.har.entries,
+	}}
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		s.t.Logf("Failed to encode HAR: %v", err)
+
+		return
+	}
+
+	if err := os.MkdirAll(s.config.HARDir, 0o750); err != nil {
+		s.t.Logf("Failed to create HAR directory: %v", err)
+
+		return
+	}
+
+	path := filepath.Join(s.config.HARDir, artifactName(s.t.Name())+".har")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		s.t.Logf("Failed to write HAR: %v", err)
+	}
+}
+
+// unsafeFileChars matches characters replaced in artifact file names.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// artifactName turns a test name into a file name.
+func artifactName(testName string) string {
+	return unsafeFileChars.ReplaceAllString(testName, "_")
+}
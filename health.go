@@ -0,0 +1,115 @@
+package e2e
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHealthTimeout = 30 * time.Second
+	// defaultPollInterval is shared with ReadinessProbe.
+	defaultPollInterval = 50 * time.Millisecond
+)
+
+// HealthCheck describes the probe New polls before returning the suite.
+type HealthCheck struct {
+	// Path is requested with GET, relative to BaseURL. Defaults to BaseURL itself.
+	Path string
+	// Status is the expected status code. Defaults to any 2xx status.
+	Status int
+	// Body, when set, must match the response body: a string matches
+	// exactly, a *regexp.Regexp or func(string) bool decides, and any other
+	// value is compared as JSON like ExpectJSON.
+	Body interface{}
+	// Timeout bounds the whole wait. Defaults to 30 seconds.
+	Timeout time.Duration
+	// Interval is the pause between probes. Defaults to 50ms.
+	Interval time.Duration
+}
+
+// waitHealthy polls the health check until it passes, returning the result
+// of the last probe when the timeout expires. Probes use the suite client
+// directly, so they are neither recorded nor replayed.
+func (s *TestSuite) waitHealthy(check HealthCheck) error {
+	timeout := cmp.Or(check.Timeout, defaultHealthTimeout)
+	interval := cmp.Or(check.Interval, defaultPollInterval)
+
+	reqURL, err := s.resolveURL(check.Path, nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(s.t.Context(), timeout)
+	defer cancel()
+
+	for probes := 1; ; probes++ {
+		lastErr := s.probeHealth(ctx, reqURL.String(), check)
+		if lastErr == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s was not healthy after %s (%d probes); last probe: %w", reqURL, timeout, probes, lastErr)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// probeHealth runs one health check, bounded by the suite timeout.
+func (s *TestSuite) probeHealth(ctx context.Context, reqURL string, check HealthCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	return check.probe(ctx, s.client, reqURL, cmp.Or(s.config.MaxCaptureSize, defaultMaxCapture))
+}
+
+// probe sends one GET to reqURL and checks the response, matching up to
+// limit bytes of the body. ReadinessProbe.HTTPPath is checked with it too.
+func (c HealthCheck) probe(ctx context.Context, client *http.Client, reqURL string, limit int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create health check: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Match the body as assertions would; only the diagnostic is truncated.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	if err != nil {
+		return fmt.Errorf("failed to read body: %w", err)
+	}
+
+	healthy := resp.StatusCode == c.Status ||
+		c.Status == 0 && resp.StatusCode >= 200 && resp.StatusCode < 300
+
+	if !healthy || (c.Body != nil && !MatchText(string(body), c.Body)) {
+		return fmt.Errorf("got %d %s with body %q; expected %s",
+			resp.StatusCode, http.StatusText(resp.StatusCode), truncateString(string(body)), describeHealth(c))
+	}
+
+	return nil
+}
+
+// describeHealth formats what a health check expects.
+func describeHealth(check HealthCheck) string {
+	parts := []string{"a 2xx status"}
+	if check.Status != 0 {
+		parts[0] = fmt.Sprintf("status %d", check.Status)
+	}
+
+	if check.Body != nil {
+		parts = append(parts, "body "+DescribeMatcher(check.Body))
+	}
+
+	return strings.Join(parts, " and ")
+}
@@ -0,0 +1,253 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RPCBuilder builds a JSON-RPC 2.0 call or batch sent as a POST request.
+type RPCBuilder struct {
+	http  *HTTPBuilder
+	calls []rpcCall
+	// selected is the index in calls that assertions apply to.
+	selected int
+}
+
+// rpcCall is a request object; notifications have no id.
+type rpcCall struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      *int64      `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcResponse is a response object.
+type rpcResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// rpcError is the error member of a response object.
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// RPC creates a builder for a JSON-RPC 2.0 call posted to Config.RPCPath.
+// Ids are generated per suite. Chain Call or Notify to send a batch;
+// assertions apply to the most recent call unless At selects another.
+//
+//	client.RPC("add", []int{1, 2}).
+//		Call("subtract", []int{5, 3}).
+//		Execute(ctx).
+//		At(0).ExpectRPCResult(3).
+//		At(1).ExpectRPCResult(2)
+func (s *TestSuite) RPC(method string, params interface{}) *RPCBuilder {
+	r := &RPCBuilder{http: s.POST(s.config.RPCPath)}
+
+	return r.Call(method, params)
+}
+
+// Call adds a call to the batch and selects it.
+func (r *RPCBuilder) Call(method string, params interface{}) *RPCBuilder {
+	id := r.http.suite.rpcID.Add(1)
+	r.calls = append(r.calls, rpcCall{JSONRPC: "2.0", ID: &id, Method: method, Params: params})
+	r.selected = len(r.calls) - 1
+
+	return r
+}
+
+// Notify adds a notification, which gets no response, to the batch.
+func (r *RPCBuilder) Notify(method string, params interface{}) *RPCBuilder {
+	r.calls = append(r.calls, rpcCall{JSONRPC: "2.0", Method: method, Params: params})
+
+	return r
+}
+
+// At selects the call, by its position in the batch, that following
+// assertions apply to.
+func (r *RPCBuilder) At(index int) *RPCBuilder {
+	if index < 0 || index >= len(r.calls) || r.calls[index].ID == nil {
+		r.http.suite.t.Fatalf("RPC batch has no call at index %d", index)
+	}
+
+	r.selected = index
+
+	return r
+}
+
+// Header sets a request header.
+func (r *RPCBuilder) Header(key, value string) *RPCBuilder {
+	r.http.Header(key, value)
+
+	return r
+}
+
+// Authorization sets the Authorization header.
+func (r *RPCBuilder) Authorization(value string) *RPCBuilder {
+	r.http.Authorization(value)
+
+	return r
+}
+
+// Timeout sets the request timeout, overriding Config.Timeout.
+func (r *RPCBuilder) Timeout(timeout time.Duration) *RPCBuilder {
+	r.http.Timeout(timeout)
+
+	return r
+}
+
+// HTTP returns the underlying HTTP request builder.
+func (r *RPCBuilder) HTTP() *HTTPBuilder {
+	return r.http
+}
+
+// Execute sends the call, or a batch array when several were added.
+func (r *RPCBuilder) Execute(ctx context.Context) *RPCBuilder {
+	var body interface{} = r.calls
+	if len(r.calls) == 1 {
+		body = r.calls[0]
+	}
+
+	r.http.Body(body).Execute(ctx)
+
+	return r
+}
+
+// ExpectStatus validates the HTTP response status code.
+func (r *RPCBuilder) ExpectStatus(statusCode int) *RPCBuilder {
+	r.http.ExpectStatus(statusCode)
+
+	return r
+}
+
+// ExpectRPCResult validates the result of the selected call, compared as
+// JSON like ExpectJSON.
+func (r *RPCBuilder) ExpectRPCResult(expected interface{}) *RPCBuilder {
+	h := r.http
+
+	h.expect(func() error {
+		resp, err := r.response()
+		if err != nil {
+			return err
+		}
+
+		assertion := r.assertion("Result mismatch")
+
+		if resp.Error != nil {
+			return errors.New(h.formatError(assertion, DescribeMatcher(expected), resp.Error.String()))
+		}
+
+		normalized, err := normalizeJSON(expected)
+		if err != nil {
+			return err
+		}
+
+		var actual interface{}
+		if err := json.Unmarshal(resp.Result, &actual); err != nil || !jsonEqual(normalized, actual) {
+			expectedJSON, _ := json.Marshal(normalized)
+
+			return errors.New(h.formatError(assertion, string(expectedJSON), string(resp.Result)))
+		}
+
+		return nil
+	})
+
+	return r
+}
+
+// ExpectRPCError validates that the selected call failed with code.
+func (r *RPCBuilder) ExpectRPCError(code int) *RPCBuilder {
+	h := r.http
+
+	h.expect(func() error {
+		resp, err := r.response()
+		if err != nil {
+			return err
+		}
+
+		if resp.Error != nil && resp.Error.Code == code {
+			return nil
+		}
+
+		actual := "result " + string(resp.Result)
+		if resp.Error != nil {
+			actual = resp.Error.String()
+		}
+
+		return errors.New(h.formatError(r.assertion("Error mismatch"), "error "+strconv.Itoa(code), actual))
+	})
+
+	return r
+}
+
+// assertion names the selected call in failure messages.
+func (r *RPCBuilder) assertion(kind string) string {
+	call := r.calls[r.selected]
+
+	return fmt.Sprintf("%s (%s, id %d)", kind, call.Method, *call.ID)
+}
+
+// response finds the response object for the selected call by id, so
+// batch replies may arrive in any order.
+func (r *RPCBuilder) response() (*rpcResponse, error) {
+	h := r.http
+
+	body, err := h.completeBody()
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := parseRPCResponses(body)
+	if err != nil {
+		return nil, errors.New(h.formatError("Invalid JSON-RPC response", "response object or batch array", err.Error()))
+	}
+
+	id := strconv.FormatInt(*r.calls[r.selected].ID, 10)
+
+	for i := range responses {
+		if string(responses[i].ID) == id {
+			return &responses[i], nil
+		}
+	}
+
+	return nil, errors.New(h.formatError(r.assertion("Missing response"), "response with id "+id, fmt.Sprintf("%d responses", len(responses))))
+}
+
+// parseRPCResponses decodes a single response object or a batch array.
+func parseRPCResponses(body []byte) ([]rpcResponse, error) {
+	body = bytes.TrimSpace(body)
+
+	if len(body) > 0 && body[0] == '[' {
+		var responses []rpcResponse
+		if err := json.Unmarshal(body, &responses); err != nil {
+			return nil, fmt.Errorf("failed to parse batch response: %w", err)
+		}
+
+		return responses, nil
+	}
+
+	var resp rpcResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return []rpcResponse{resp}, nil
+}
+
+// String formats the error for failure messages.
+func (e *rpcError) String() string {
+	s := fmt.Sprintf("error %d: %s", e.Code, e.Message)
+	if len(e.Data) > 0 {
+		s += " " + string(e.Data)
+	}
+
+	return s
+}
@@ -0,0 +1,59 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// MatchText reports whether actual satisfies expected. The same rules apply
+// to SSE and WebSocket expectations, health checks and the testserver mock.
+// expected may be:
+//
+//   - nil, matching anything
+//   - a string, matching exactly
+//   - a *regexp.Regexp, matching when the expression matches
+//   - a func(string) bool, matching when it returns true
+//   - any other value, compared as JSON like ExpectJSON
+func MatchText(actual string, expected interface{}) bool {
+	switch exp := expected.(type) {
+	case nil:
+		return true
+	case string:
+		return actual == exp
+	case *regexp.Regexp:
+		return exp.MatchString(actual)
+	case func(string) bool:
+		return exp(actual)
+	default:
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(actual), &parsed); err != nil {
+			return false
+		}
+
+		normalized, err := normalizeJSON(expected)
+
+		return err == nil && jsonEqual(normalized, parsed)
+	}
+}
+
+// DescribeMatcher formats an expected matcher for error reports.
+func DescribeMatcher(expected interface{}) string {
+	switch exp := expected.(type) {
+	case nil:
+		return "<any>"
+	case string:
+		return exp
+	case *regexp.Regexp:
+		return "matching /" + exp.String() + "/"
+	case func(string) bool:
+		return "<custom matcher>"
+	default:
+		data, err := json.Marshal(exp)
+		if err != nil {
+			return fmt.Sprintf("%v", exp)
+		}
+
+		return string(data)
+	}
+}
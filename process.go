@@ -0,0 +1,464 @@
+package e2e
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// portPlaceholder in ProcessOptions.Args and Env is replaced with a free
+// TCP port chosen before the process starts.
+const portPlaceholder = "{{port}}"
+
+const (
+	defaultReadyTimeout = 30 * time.Second
+	defaultStopTimeout  = 10 * time.Second
+)
+
+// ProcessOptions configures StartProcess.
+type ProcessOptions struct {
+	// Build treats the command as a Go package, such as "./cmd/server",
+	// and builds it with go build before starting it.
+	Build bool
+	// BuildFlags are passed to go build, such as "-race".
+	BuildFlags []string
+	// Args are the command line arguments. {{port}} is replaced with a free
+	// port, which also becomes the process address.
+	Args []string
+	// Env is added to the test's environment. {{port}} is replaced as in Args.
+	Env []string
+	// Dir is the working directory for the build and the process.
+	Dir string
+	// Addr is the host:port the process listens on, when it is neither
+	// chosen through {{port}} nor discovered from the logs.
+	Addr string
+	// Ready decides when the process can take requests.
+	Ready ReadinessProbe
+	// StopTimeout is how long the process has to exit after SIGINT before
+	// it is killed. Defaults to 10 seconds.
+	StopTimeout time.Duration
+	// Coverage collects Go coverage from the process. Without Build, the
+	// command must already be built with -cover.
+	Coverage *CoverageOptions
+}
+
+// ReadinessProbe decides when a started process is ready. Every configured
+// check must pass; with none configured the process is ready once started.
+type ReadinessProbe struct {
+	// LogLine waits for an output line matching the expression. A submatch
+	// named addr, as in `listening on (?P<addr>\S+)`, sets the address.
+	LogLine *regexp.Regexp
+	// TCP waits until the address accepts connections.
+	TCP bool
+	// HTTPPath waits until a GET of the path answers with a 2xx status,
+	// checked like Config.WaitFor.
+	HTTPPath string
+	// Timeout bounds the wait. Defaults to 30 seconds.
+	Timeout time.Duration
+	// Interval is the pause between checks. Defaults to 50ms.
+	Interval time.Duration
+}
+
+// Process is a service started by StartProcess.
+type Process struct {
+	// Addr is the host:port the process listens on, if known.
+	Addr string
+	// BaseURL is http://Addr, or the URL logged by the process.
+	BaseURL string
+
+	tb          testing.TB
+	name        string
+	cmd         *exec.Cmd
+	output      *processOutput
+	exited      chan struct{}
+	waitErr     error
+	stopTimeout time.Duration
+	stopOnce    sync.Once
+	coverage    *CoverageOptions
+	coverDir    string
+}
+
+// StartProcess starts command, or builds and starts it when opts.Build is
+// set, and waits until it is ready. Its output is written to the test log.
+// The process is stopped with SIGINT when the test ends, and killed if it
+// does not exit within opts.StopTimeout.
+func StartProcess(tb testing.TB, command string, opts ProcessOptions) *Process {
+	tb.Helper()
+
+	var coverDir string
+
+	if opts.Coverage != nil {
+		dir, err := opts.Coverage.instrument(&opts)
+		if err != nil {
+			tb.Fatalf("Failed to set up coverage: %v", err)
+		}
+
+		coverDir = dir
+	}
+
+	if opts.Build {
+		binary, err := buildBinary(tb, command, opts)
+		if err != nil {
+			tb.Fatalf("Failed to build %s: %v", command, err)
+		}
+
+		command = binary
+	}
+
+	args, env, addr, err := substitutePort(opts)
+	if err != nil {
+		tb.Fatalf("Failed to choose a port: %v", err)
+	}
+
+	p := &Process{
+		Addr:        addr,
+		tb:          tb,
+		name:        filepath.Base(command),
+		exited:      make(chan struct{}),
+		stopTimeout: cmp.Or(opts.StopTimeout, defaultStopTimeout),
+		coverage:    opts.Coverage,
+		coverDir:    coverDir,
+	}
+	p.output = &processOutput{tb: tb, prefix: p.name, changed: make(chan struct{}, 1)}
+
+	if err := p.start(command, args, env, opts.Dir); err != nil {
+		tb.Fatalf("Failed to start %s: %v", command, err)
+	}
+
+	tb.Cleanup(p.Stop)
+
+	if err := p.waitReady(opts.Ready); err != nil {
+		p.Stop()
+		tb.Fatalf("Process %s is not ready: %v\n%s", p.name, err, p.output.String())
+	}
+
+	if p.BaseURL == "" && p.Addr != "" {
+		p.BaseURL = "http://" + p.Addr
+	}
+
+	return p
+}
+
+// start launches the process and waits for it in the background.
+func (p *Process) start(command string, args, env []string, dir string) error {
+	// The test context ends just before cleanups run, interrupting the
+	// process; Stop then waits for it to exit.
+	p.cmd = exec.CommandContext(p.tb.Context(), command, args...) //nolint:gosec // Running the configured service is the point.
+	p.cmd.Cancel = func() error { return p.cmd.Process.Signal(os.Interrupt) }
+	p.cmd.Dir = dir
+	p.cmd.Env = append(os.Environ(), env...)
+	p.cmd.Stdout = p.output
+	p.cmd.Stderr = p.output
+	p.cmd.WaitDelay = p.stopTimeout
+
+	if err := p.cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start process: %w", err)
+	}
+
+	go func() {
+		p.waitErr = p.cmd.Wait()
+		p.output.flush()
+		close(p.exited)
+	}()
+
+	return nil
+}
+
+// New creates a test suite whose BaseURL points at the process.
+func (p *Process) New(tb testing.TB, config Config) *TestSuite {
+	tb.Helper()
+
+	config.BaseURL = p.BaseURL
+
+	return New(tb, config)
+}
+
+// Logs returns everything the process has written so far.
+func (p *Process) Logs() string {
+	return p.output.String()
+}
+
+// Stop interrupts the process and waits for it to exit, killing it after the
+// stop timeout, then writes its coverage profile if requested. It is safe
+// to call more than once.
+func (p *Process) Stop() {
+	p.stopOnce.Do(func() {
+		p.interrupt()
+
+		if p.coverage != nil {
+			p.writeCoverage()
+		}
+	})
+}
+
+// interrupt sends SIGINT unless the process already exited, and waits.
+func (p *Process) interrupt() {
+	select {
+	case <-p.exited:
+		return
+	default:
+	}
+
+	if err := p.cmd.Process.Signal(os.Interrupt); err != nil {
+		_ = p.cmd.Process.Kill()
+	}
+
+	select {
+	case <-p.exited:
+	case <-time.After(p.stopTimeout):
+		p.tb.Logf("Process %s did not exit within %s; killing it", p.name, p.stopTimeout)
+		_ = p.cmd.Process.Kill()
+		<-p.exited
+	}
+}
+
+// buildBinary builds the Go package pkg into a temporary directory.
+func buildBinary(tb testing.TB, pkg string, opts ProcessOptions) (string, error) {
+	name := filepath.Base(pkg)
+	if name == "." || name == string(filepath.Separator) {
+		name = "service"
+	}
+
+	binary := filepath.Join(tb.TempDir(), name)
+
+	args := append([]string{"build", "-o", binary}, opts.BuildFlags...)
+	args = append(args, pkg)
+
+	cmd := exec.CommandContext(tb.Context(), "go", args...) //nolint:gosec // The package comes from the test itself.
+	cmd.Dir = opts.Dir
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("go build: %w\n%s", err, out)
+	}
+
+	return binary, nil
+}
+
+// substitutePort replaces {{port}} in the arguments and environment with a
+// free port, returning the resulting address when a port was chosen.
+func substitutePort(opts ProcessOptions) ([]string, []string, string, error) {
+	placeholder := func(s string) bool { return strings.Contains(s, portPlaceholder) }
+	if !slices.ContainsFunc(opts.Args, placeholder) && !slices.ContainsFunc(opts.Env, placeholder) {
+		return opts.Args, opts.Env, opts.Addr, nil
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to listen: %w", err)
+	}
+
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port) //nolint:forcetypeassert // A TCP listener has a TCP address.
+	_ = listener.Close()
+
+	replace := func(values []string) []string {
+		replaced := make([]string, len(values))
+		for i, value := range values {
+			replaced[i] = strings.ReplaceAll(value, portPlaceholder, port)
+		}
+
+		return replaced
+	}
+
+	return replace(opts.Args), replace(opts.Env), net.JoinHostPort("127.0.0.1", port), nil
+}
+
+// waitReady polls the probe until every check passes.
+func (p *Process) waitReady(probe ReadinessProbe) error {
+	timeout := cmp.Or(probe.Timeout, defaultReadyTimeout)
+	interval := cmp.Or(probe.Interval, defaultPollInterval)
+
+	ctx, cancel := context.WithTimeout(p.tb.Context(), timeout)
+	defer cancel()
+
+	if probe.LogLine != nil {
+		if err := p.waitLogLine(ctx, probe.LogLine); err != nil {
+			return err
+		}
+	}
+
+	if !probe.TCP && probe.HTTPPath == "" {
+		return nil
+	}
+
+	if p.Addr == "" && p.BaseURL == "" {
+		return errors.New("no address to probe; use {{port}}, Addr or a LogLine with an addr submatch")
+	}
+
+	var lastErr error
+
+	for {
+		if lastErr = p.probe(ctx, probe); lastErr == nil {
+			return nil
+		}
+
+		select {
+		case <-p.exited:
+			return fmt.Errorf("process exited before it was ready: %w", p.exitError())
+		case <-ctx.Done():
+			return fmt.Errorf("not ready after %s: %w", timeout, lastErr)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// waitLogLine waits for an output line matching pattern and takes the
+// address from its addr submatch.
+func (p *Process) waitLogLine(ctx context.Context, pattern *regexp.Regexp) error {
+	for seen := 0; ; {
+		lines := p.output.linesSoFar()
+		for _, line := range lines[seen:] {
+			if p.matchLogLine(pattern, line) {
+				return nil
+			}
+		}
+
+		seen = len(lines)
+
+		select {
+		case <-p.output.changed:
+		case <-p.exited:
+			// Output is complete once the process has exited.
+			if lines := p.output.linesSoFar(); len(lines) > seen {
+				continue
+			}
+
+			return fmt.Errorf("process exited before logging a line matching /%s/: %w", pattern, p.exitError())
+		case <-ctx.Done():
+			return fmt.Errorf("no line matching /%s/ was logged: %w", pattern, ctx.Err())
+		}
+	}
+}
+
+func (p *Process) matchLogLine(pattern *regexp.Regexp, line string) bool {
+	match := pattern.FindStringSubmatch(line)
+	if match == nil {
+		return false
+	}
+
+	if i := pattern.SubexpIndex("addr"); i > 0 && match[i] != "" {
+		addr := match[i]
+		if strings.Contains(addr, "://") {
+			p.BaseURL = strings.TrimSuffix(addr, "/")
+			addr = addr[strings.Index(addr, "://")+3:]
+		}
+
+		p.Addr = strings.TrimSuffix(addr, "/")
+	}
+
+	return true
+}
+
+// probe runs the TCP and HTTP checks once.
+func (p *Process) probe(ctx context.Context, probe ReadinessProbe) error {
+	if probe.TCP {
+		var d net.Dialer
+
+		conn, err := d.DialContext(ctx, "tcp", p.Addr)
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+
+		_ = conn.Close()
+	}
+
+	if probe.HTTPPath == "" {
+		return nil
+	}
+
+	baseURL := p.BaseURL
+	if baseURL == "" {
+		baseURL = "http://" + p.Addr
+	}
+
+	check := HealthCheck{Path: probe.HTTPPath}
+
+	return check.probe(ctx, http.DefaultClient, baseURL+probe.HTTPPath, defaultMaxCapture)
+}
+
+// exitError describes how the process exited.
+func (p *Process) exitError() error {
+	if p.waitErr != nil {
+		return p.waitErr
+	}
+
+	return errors.New("exit status 0")
+}
+
+// processOutput logs process output line by line and keeps a copy for
+// readiness checks and failure reports.
+type processOutput struct {
+	tb     testing.TB
+	prefix string
+
+	mu      sync.Mutex
+	partial []byte
+	all     []string
+	// changed is signaled when lines are added.
+	changed chan struct{}
+}
+
+func (o *processOutput) Write(data []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.partial = append(o.partial, data...)
+
+	for {
+		i := bytes.IndexByte(o.partial, '\n')
+		if i < 0 {
+			break
+		}
+
+		o.addLine(string(bytes.TrimRight(o.partial[:i], "\r")))
+		o.partial = o.partial[i+1:]
+	}
+
+	return len(data), nil
+}
+
+// flush emits a final line without a newline.
+func (o *processOutput) flush() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.partial) > 0 {
+		o.addLine(string(o.partial))
+		o.partial = nil
+	}
+}
+
+func (o *processOutput) addLine(line string) {
+	o.all = append(o.all, line)
+	o.tb.Logf("[%s] %s", o.prefix, line)
+
+	select {
+	case o.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (o *processOutput) linesSoFar() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return slices.Clone(o.all)
+}
+
+// String returns the output so far.
+func (o *processOutput) String() string {
+	return strings.Join(o.linesSoFar(), "\n")
+}
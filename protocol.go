@@ -0,0 +1,146 @@
+package e2e
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// Protocol selects the HTTP protocol used by the transport.
+type Protocol string
+
+const (
+	// ProtocolAuto negotiates the protocol like net/http: HTTP/2 over TLS when
+	// the server supports it, HTTP/1.1 otherwise.
+	ProtocolAuto Protocol = ""
+	// ProtocolHTTP1 forces HTTP/1.1.
+	ProtocolHTTP1 Protocol = "HTTP/1.1"
+	// ProtocolHTTP2 forces HTTP/2 over TLS.
+	ProtocolHTTP2 Protocol = "HTTP/2"
+	// ProtocolH2C uses unencrypted HTTP/2 with prior knowledge.
+	ProtocolH2C Protocol = "h2c"
+)
+
+// protocols converts p into the net/http protocol set.
+func (p Protocol) protocols() (*http.Protocols, error) {
+	protocols := new(http.Protocols)
+
+	switch p {
+	case ProtocolHTTP1:
+		protocols.SetHTTP1(true)
+	case ProtocolHTTP2:
+		protocols.SetHTTP2(true)
+	case ProtocolH2C:
+		protocols.SetUnencryptedHTTP2(true)
+	case ProtocolAuto:
+		return nil, nil //nolint:nilnil // A nil set keeps the transport defaults.
+	default:
+		return nil, fmt.Errorf("unknown protocol %q", string(p))
+	}
+
+	return protocols, nil
+}
+
+// withProtocols returns a clone of base restricted to protocols.
+//
+// A transport that has already sent requests may have added "h2" and
+// "http/1.1" to its ALPN list; those are dropped so the clone only
+// advertises the protocols it is allowed to speak.
+func withProtocols(base *http.Transport, protocols *http.Protocols) *http.Transport {
+	transport := base.Clone()
+	transport.Protocols = protocols
+
+	if transport.TLSClientConfig != nil {
+		transport.TLSClientConfig.NextProtos = slices.DeleteFunc(slices.Clone(transport.TLSClientConfig.NextProtos), func(proto string) bool {
+			return proto == "h2" || proto == "http/1.1"
+		})
+	}
+
+	return transport
+}
+
+// Protocol selects the HTTP protocol for this request, overriding Config.Protocol.
+func (h *HTTPBuilder) Protocol(protocol Protocol) *HTTPBuilder {
+	h.protocol = protocol
+
+	return h
+}
+
+// transportFor returns a transport speaking protocol. Transports are cached
+// per protocol so connections are reused across requests.
+func (s *TestSuite) transportFor(protocol Protocol) (http.RoundTripper, error) {
+	if protocol == s.config.Protocol {
+		return s.client.Transport, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if transport, ok := s.transports[protocol]; ok {
+		return transport, nil
+	}
+
+	base := s.client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, errors.New("protocol selection requires an *http.Transport")
+	}
+
+	protocols, err := protocol.protocols()
+	if err != nil {
+		return nil, err
+	}
+
+	transport = withProtocols(transport, protocols)
+
+	if s.transports == nil {
+		s.transports = make(map[Protocol]http.RoundTripper)
+	}
+
+	s.transports[protocol] = transport
+
+	return transport, nil
+}
+
+// ExpectProto validates the response protocol, such as "HTTP/2.0".
+func (h *HTTPBuilder) ExpectProto(proto string) *HTTPBuilder {
+	return h.expect(func() error {
+		if h.resp.Proto == proto {
+			return nil
+		}
+
+		return errors.New(h.formatError("Protocol mismatch", proto, h.resp.Proto))
+	})
+}
+
+// Trailers reads the response body and returns the response trailers.
+func (h *HTTPBuilder) Trailers() http.Header {
+	if h.resp == nil {
+		h.suite.t.Fatal("Request not executed. Call Execute() first.")
+	}
+
+	h.readResponseBody()
+
+	return h.resp.Trailer
+}
+
+// ExpectTrailer validates a response trailer. The body is read to reach the trailers.
+func (h *HTTPBuilder) ExpectTrailer(key, value string) *HTTPBuilder {
+	return h.expect(func() error {
+		h.readResponseBody()
+
+		actualValue := h.resp.Trailer.Get(key)
+		if actualValue == value {
+			return nil
+		}
+
+		assertion := fmt.Sprintf("Trailer mismatch (%s)", key)
+
+		return errors.New(h.formatError(assertion, value, actualValue))
+	})
+}
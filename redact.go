@@ -0,0 +1,35 @@
+package e2e
+
+import (
+	"net/http"
+)
+
+// redactedValue replaces the values of redacted headers in artifacts.
+const redactedValue = "[REDACTED]"
+
+// defaultRedactHeaders are redacted when Config.RedactHeaders is nil.
+var defaultRedactHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization", "Set-Cookie"}
+
+// redactHeaders returns a copy of headers with the values of the
+// configured sensitive headers replaced. Failure messages are not redacted;
+// only artifacts written to disk are.
+func (s *TestSuite) redactHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	if redacted == nil {
+		return http.Header{}
+	}
+
+	names := s.config.RedactHeaders
+	if names == nil {
+		names = defaultRedactHeaders
+	}
+
+	for _, name := range names {
+		values := redacted[http.CanonicalHeaderKey(name)]
+		for i := range values {
+			values[i] = redactedValue
+		}
+	}
+
+	return redacted
+}
@@ -0,0 +1,136 @@
+package e2e
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// defaultMaxRedirects matches the limit applied by net/http.
+const defaultMaxRedirects = 10
+
+// RedirectHop describes a redirect response received while executing a request.
+type RedirectHop struct {
+	// URL is the request URL that was redirected.
+	URL string
+	// StatusCode is the redirect status code.
+	StatusCode int
+	// Location is the raw Location header of the redirect response.
+	Location string
+}
+
+// FollowRedirects controls whether redirects are followed. When disabled the
+// first redirect response is returned as-is so it can be asserted.
+func (h *HTTPBuilder) FollowRedirects(follow bool) *HTTPBuilder {
+	h.followRedirects = &follow
+
+	return h
+}
+
+// MaxRedirects follows at most n redirects, failing the request beyond that.
+func (h *HTTPBuilder) MaxRedirects(n int) *HTTPBuilder {
+	h.FollowRedirects(n > 0)
+	h.maxRedirects = n
+
+	return h
+}
+
+// Redirects returns every redirect hop received by the last execution.
+func (h *HTTPBuilder) Redirects() []RedirectHop {
+	return h.redirects
+}
+
+// ExpectRedirect validates that the response is a redirect with the given
+// status and Location. location may be the raw header value or the resolved
+// absolute URL. Use it together with FollowRedirects(false).
+func (h *HTTPBuilder) ExpectRedirect(statusCode int, location string) *HTTPBuilder {
+	return h.expect(func() error {
+		actualLocation := h.resp.Header.Get("Location")
+		if h.resp.StatusCode == statusCode && h.locationMatches(actualLocation, location) {
+			return nil
+		}
+
+		expected := fmt.Sprintf("%d %s -> %s", statusCode, http.StatusText(statusCode), location)
+		actual := fmt.Sprintf("%d %s -> %s", h.resp.StatusCode, http.StatusText(h.resp.StatusCode), actualLocation)
+
+		return errors.New(h.formatError("Redirect mismatch", expected, actual))
+	})
+}
+
+// locationMatches compares a Location header with the expected location.
+func (h *HTTPBuilder) locationMatches(actual, expected string) bool {
+	if actual == expected {
+		return true
+	}
+
+	resolved, err := h.resp.Request.URL.Parse(actual)
+
+	return err == nil && resolved.String() == expected
+}
+
+// httpClient returns the suite client with a redirect policy that records
+// every hop and applies the per-request limits. The copy shares the suite
+// transport, so connections are still pooled.
+func (h *HTTPBuilder) httpClient() (*http.Client, error) {
+	client := *h.suite.client
+	next := client.CheckRedirect
+
+	if h.protocol != ProtocolAuto {
+		transport, err := h.suite.transportFor(h.protocol)
+		if err != nil {
+			return nil, err
+		}
+
+		client.Transport = transport
+	}
+
+	if h.suite.cassette != nil {
+		client.Transport = &cassetteTransport{cassette: h.suite.cassette, next: client.Transport}
+	}
+
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		h.redirects = append(h.redirects, RedirectHop{
+			URL:        via[len(via)-1].URL.String(),
+			StatusCode: req.Response.StatusCode,
+			Location:   req.Response.Header.Get("Location"),
+		})
+
+		if h.followRedirects != nil && !*h.followRedirects {
+			return http.ErrUseLastResponse
+		}
+
+		if h.maxRedirects > 0 && len(via) > h.maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", h.maxRedirects)
+		}
+
+		if next != nil {
+			return next(req, via)
+		}
+
+		if len(via) >= defaultMaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", defaultMaxRedirects)
+		}
+
+		return nil
+	}
+
+	return &client, nil
+}
+
+// formatRedirects describes the redirect chain for error reports.
+func (h *HTTPBuilder) formatRedirects() string {
+	if len(h.redirects) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("\nRedirects:\n")
+
+	for i, hop := range h.redirects {
+		fmt.Fprintf(&sb, "  #%d %s -> %d %s\n", i+1, hop.URL, hop.StatusCode, hop.Location)
+	}
+
+	return sb.String()
+}
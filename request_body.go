@@ -0,0 +1,196 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// errBodyConsumed is returned when a stream body would have to be sent twice.
+var errBodyConsumed = errors.New("request body stream was already sent and cannot be replayed; use a func(io.Writer) error body to allow retries")
+
+// ContentLength sets the Content-Length sent with the body. It is needed
+// for io.Reader and generator bodies, which are otherwise sent chunked. A
+// value that disagrees with the body makes the request fail.
+func (h *HTTPBuilder) ContentLength(length int64) *HTTPBuilder {
+	h.contentLength = &length
+
+	return h
+}
+
+// Chunked sends the body with chunked transfer encoding even when its
+// length is known.
+func (h *HTTPBuilder) Chunked() *HTTPBuilder {
+	h.chunked = true
+
+	return h
+}
+
+// Throttle limits the upload to bytesPerSecond to simulate a slow client.
+func (h *HTTPBuilder) Throttle(bytesPerSecond int) *HTTPBuilder {
+	h.throttle = bytesPerSecond
+
+	return h
+}
+
+// isStreamBody reports whether the body is produced while it is sent rather
+// than serialized up front.
+func (h *HTTPBuilder) isStreamBody() bool {
+	switch h.body.(type) {
+	case io.Reader, func(io.Writer) error:
+		return true
+	default:
+		return false
+	}
+}
+
+// bodyReplayable reports whether the body can be sent again for a retry.
+func (h *HTTPBuilder) bodyReplayable() bool {
+	_, stream := h.body.(io.Reader)
+
+	return !stream
+}
+
+// attachBody sets a fresh body for one attempt on req, along with its
+// length and, for replayable bodies, GetBody so redirects can resend it.
+func (h *HTTPBuilder) attachBody(ctx context.Context, req *http.Request) error {
+	if h.body == nil {
+		return nil
+	}
+
+	body, length, err := h.openBody(ctx)
+	if err != nil {
+		return err
+	}
+
+	req.Body = body
+	req.ContentLength = length
+
+	switch {
+	case h.chunked:
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+	case h.contentLength != nil:
+		req.ContentLength = *h.contentLength
+	case length == 0:
+		req.Body = http.NoBody
+	}
+
+	if h.bodyReplayable() {
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, _, err := h.openBody(ctx)
+
+			return body, err
+		}
+	}
+
+	return nil
+}
+
+// openBody returns a reader over the request body and its length, or -1
+// when the length is not known in advance. Stream bodies are captured in
+// h.sent for error reports, before compression.
+func (h *HTTPBuilder) openBody(ctx context.Context) (io.ReadCloser, int64, error) {
+	var rc io.ReadCloser
+
+	switch body := h.body.(type) {
+	case io.Reader:
+		if h.streamSent {
+			return nil, 0, errBodyConsumed
+		}
+
+		h.streamSent = true
+		rc = io.NopCloser(body)
+	case func(io.Writer) error:
+		pr, pw := io.Pipe()
+
+		go func() {
+			pw.CloseWithError(body(pw))
+		}()
+
+		rc = pr
+	default:
+		data := h.requestBody
+		if h.encodedBody != nil {
+			data = h.encodedBody
+		}
+
+		return io.NopCloser(h.throttled(ctx, bytes.NewReader(data))), int64(len(data)), nil
+	}
+
+	h.sent = &captureWriter{limit: maxBodySize}
+	rc = &readCloser{Reader: io.TeeReader(rc, h.sent), Closer: rc}
+
+	if h.compression != EncodingIdentity {
+		rc = compressStream(h.compression, rc)
+	}
+
+	return &readCloser{Reader: h.throttled(ctx, rc), Closer: rc}, -1, nil
+}
+
+// throttled limits r to the configured upload rate, if any.
+func (h *HTTPBuilder) throttled(ctx context.Context, r io.Reader) io.Reader {
+	if h.throttle <= 0 {
+		return r
+	}
+
+	return &throttledReader{ctx: ctx, r: r, rate: h.throttle, start: time.Now()}
+}
+
+// readCloser pairs a reader with the closer that releases its source.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// compressStream encodes src on the fly. Closing the result closes src.
+func compressStream(encoding Encoding, src io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	go func() {
+		defer src.Close()
+
+		enc, err := encoder(encoding, pw)
+		if err == nil {
+			_, err = io.Copy(enc, src)
+			if closeErr := enc.Close(); err == nil {
+				err = closeErr
+			}
+		}
+
+		pw.CloseWithError(err)
+	}()
+
+	return pr
+}
+
+// throttledReader delivers at most rate bytes per second.
+type throttledReader struct {
+	ctx   context.Context //nolint:containedctx // Bounds the sleeps of a single upload.
+	r     io.Reader
+	rate  int
+	start time.Time
+	read  int64
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	// Read in slices of a tenth of a second so the rate stays smooth.
+	if chunk := max(t.rate/10, 1); len(p) > chunk {
+		p = p[:chunk]
+	}
+
+	n, err := t.r.Read(p)
+	t.read += int64(n)
+
+	due := time.Duration(float64(t.read) / float64(t.rate) * float64(time.Second))
+	if wait := due - time.Since(t.start); wait > 0 {
+		if sleepErr := sleepContext(t.ctx, wait); sleepErr != nil {
+			return n, sleepErr
+		}
+	}
+
+	return n, err //nolint:wrapcheck // Read must return io.EOF unwrapped.
+}
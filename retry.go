@@ -0,0 +1,230 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+//
+// Zero values fall back to the defaults documented on each field.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. Defaults to 3.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt. Defaults to 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the computed backoff and any Retry-After wait.
+	// Defaults to 5s.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after each attempt. Defaults to 2.
+	Multiplier float64
+	// Jitter randomly shortens each backoff by up to this fraction (0 to 1). Defaults to 0.2.
+	Jitter float64
+	// RetryOnStatus lists response status codes that trigger a retry.
+	// Defaults to 429, 502, 503 and 504 when nil.
+	RetryOnStatus []int
+	// RetryOnError reports whether a transport error triggers a retry.
+	// Defaults to IsTransientError.
+	RetryOnError func(err error) bool
+}
+
+// attempt records the outcome of a single request attempt.
+type attempt struct {
+	number     int
+	statusCode int
+	err        error
+	duration   time.Duration
+	wait       time.Duration
+}
+
+// defaultRetryOnStatus lists the status codes retried when RetryOnStatus is nil.
+var defaultRetryOnStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Retry sets the retry policy for this request, overriding Config.Retry.
+func (h *HTTPBuilder) Retry(policy RetryPolicy) *HTTPBuilder {
+	h.retry = &policy
+
+	return h
+}
+
+// retryPolicy returns the effective policy for the request, or nil when retries are disabled.
+func (h *HTTPBuilder) retryPolicy() *RetryPolicy {
+	policy := h.retry
+	if policy == nil {
+		policy = h.suite.config.Retry
+	}
+
+	if policy == nil {
+		return nil
+	}
+
+	p := *policy
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 5 * time.Second
+	}
+
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+
+	if p.Jitter <= 0 || p.Jitter > 1 {
+		p.Jitter = 0.2
+	}
+
+	if p.RetryOnStatus == nil {
+		p.RetryOnStatus = defaultRetryOnStatus
+	}
+
+	if p.RetryOnError == nil {
+		p.RetryOnError = IsTransientError
+	}
+
+	return &p
+}
+
+// shouldRetry reports whether another attempt should follow attempt number
+// and how long to wait before it.
+func (p *RetryPolicy) shouldRetry(number int, resp *http.Response, err error) (time.Duration, bool) {
+	if p == nil || number >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if err != nil {
+		return p.backoff(number), p.RetryOnError(err)
+	}
+
+	if !slices.Contains(p.RetryOnStatus, resp.StatusCode) {
+		return 0, false
+	}
+
+	if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		return min(wait, p.MaxBackoff), true
+	}
+
+	return p.backoff(number), true
+}
+
+// backoff computes the exponential backoff with jitter after attempt number.
+func (p *RetryPolicy) backoff(number int) time.Duration {
+	backoff := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(number-1))
+	backoff = math.Min(backoff, float64(p.MaxBackoff))
+	backoff -= backoff * p.Jitter * rand.Float64() //nolint:gosec // Jitter does not need a cryptographic source.
+
+	return time.Duration(backoff)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// IsTransientError reports whether err is a connection-level failure that
+// is likely to succeed on retry, such as a refused or reset connection or a
+// network timeout. Context cancellation is never considered transient.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// waitFits reports whether waiting d ends before ctx's deadline.
+func waitFits(ctx context.Context, d time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+
+	return !ok || time.Now().Add(d).Before(deadline)
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wait interrupted: %w", ctx.Err())
+	}
+}
+
+// discardBody drains and closes a response body so the connection can be reused.
+func discardBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodySize))
+	_ = resp.Body.Close()
+}
+
+// formatAttempts describes the attempt history when a request was retried.
+func (h *HTTPBuilder) formatAttempts() string {
+	if len(h.attempts) <= 1 {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "\nAttempts: %d\n", len(h.attempts))
+
+	for _, a := range h.attempts {
+		outcome := fmt.Sprintf("%d %s", a.statusCode, http.StatusText(a.statusCode))
+		if a.err != nil {
+			outcome = "error: " + a.err.Error()
+		}
+
+		fmt.Fprintf(&sb, "  #%d %s (%s)", a.number, outcome, a.duration.Round(time.Millisecond))
+
+		if a.wait > 0 {
+			fmt.Fprintf(&sb, ", retried after %s", a.wait.Round(time.Millisecond))
+		}
+
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,409 @@
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SSEEvent is a single Server-Sent Event.
+type SSEEvent struct {
+	// Type is the event type; "message" when the event field is omitted.
+	Type string
+	// Data is the event data, with multiple data lines joined by newlines.
+	Data string
+	// ID is the last event ID in effect when the event was dispatched.
+	ID string
+	// Retry is the reconnection time announced with the event, if any.
+	Retry time.Duration
+}
+
+// SSEStream asserts on a text/event-stream response as events arrive.
+type SSEStream struct {
+	h      *HTTPBuilder
+	reader *sseReader
+
+	// pending holds expectations not yet matched; consumed is the number
+	// of received events already checked against them.
+	pending  []sseExpectation
+	consumed int
+}
+
+// sseExpectation describes an expected event.
+type sseExpectation struct {
+	eventType string
+	data      interface{}
+	id        *string
+	retry     time.Duration
+}
+
+// ExpectSSE validates that the response is an event stream and starts
+// parsing it incrementally. Chain Event expectations and finish with Within.
+// The stream is read under the request context, so it ends when Timeout or
+// Config.Timeout expires; set Timeout above the longest Within wait.
+//
+//	client.GET("/events").
+//		Execute(ctx).
+//		ExpectSSE().
+//		Event("created", `{"id":1}`).
+//		Event("deleted", nil).ID("2").
+//		Within(5 * time.Second)
+func (h *HTTPBuilder) ExpectSSE() *SSEStream {
+	if h.resp == nil {
+		h.suite.t.Fatal("Request not executed. Call Execute() first.")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(h.resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		h.fail(h.formatError("Content-Type mismatch", "text/event-stream", h.resp.Header.Get("Content-Type")))
+	}
+
+	stream := &SSEStream{h: h, reader: newSSEReader(h.resp.Body)}
+	h.sse = stream
+
+	return stream
+}
+
+// Event expects the next matching event to have eventType and data. Events
+// must arrive in the order they are expected; unrelated events in between
+// are ignored. data is matched as described by MatchText, so nil accepts
+// any data and non-string values are compared as JSON.
+func (s *SSEStream) Event(eventType string, data interface{}) *SSEStream {
+	s.pending = append(s.pending, sseExpectation{eventType: eventType, data: data})
+
+	return s
+}
+
+// ID requires the most recently expected event to carry id.
+func (s *SSEStream) ID(id string) *SSEStream {
+	if len(s.pending) == 0 {
+		s.h.suite.t.Fatal("ID must follow an Event expectation")
+	}
+
+	s.pending[len(s.pending)-1].id = &id
+
+	return s
+}
+
+// Retry requires the most recently expected event to announce retry.
+func (s *SSEStream) Retry(retry time.Duration) *SSEStream {
+	if len(s.pending) == 0 {
+		s.h.suite.t.Fatal("Retry must follow an Event expectation")
+	}
+
+	s.pending[len(s.pending)-1].retry = retry
+
+	return s
+}
+
+// Within waits up to d for every pending expectation to be met, failing
+// the test with the events received so far otherwise.
+func (s *SSEStream) Within(d time.Duration) *SSEStream {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	for {
+		events, err := s.reader.snapshot()
+		s.match(events)
+
+		if len(s.pending) == 0 {
+			return s
+		}
+
+		if err != nil {
+			s.fail(s.endReason(err))
+		}
+
+		select {
+		case <-s.reader.notify:
+		case <-timer.C:
+			s.fail(fmt.Sprintf("timed out after %s", d))
+		}
+	}
+}
+
+// endReason explains why the stream ended, pointing at the request timeout
+// when the deadline cut it off.
+func (s *SSEStream) endReason(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Sprintf("stream ended: %v; it is read under the request timeout of %s, raise it with Timeout",
+			err, s.h.requestTimeout())
+	}
+
+	return fmt.Sprintf("stream ended: %v", err)
+}
+
+// Events returns every event received so far.
+func (s *SSEStream) Events() []SSEEvent {
+	events, _ := s.reader.snapshot()
+
+	return events
+}
+
+// Reconnect closes the stream and re-executes the request with the
+// Last-Event-ID header set, waiting for the server-announced retry delay
+// first. It returns the new stream.
+func (s *SSEStream) Reconnect(ctx context.Context) *SSEStream {
+	lastID, retry := s.reader.resumeState()
+
+	if err := sleepContext(ctx, retry); err != nil {
+		s.h.suite.t.Fatalf("Failed to reconnect event stream: %v", err)
+	}
+
+	s.h.closeResponse()
+
+	if lastID != "" {
+		s.h.Header("Last-Event-ID", lastID)
+	}
+
+	return s.h.Execute(ctx).ExpectSSE()
+}
+
+// match consumes events against the pending expectations in order.
+func (s *SSEStream) match(events []SSEEvent) {
+	for ; s.consumed < len(events) && len(s.pending) > 0; s.consumed++ {
+		if s.pending[0].matches(events[s.consumed]) {
+			s.pending = s.pending[1:]
+		}
+	}
+}
+
+// fail reports the unmet expectations together with the received events.
+func (s *SSEStream) fail(reason string) {
+	expected := make([]string, 0, len(s.pending))
+	for _, exp := range s.pending {
+		expected = append(expected, exp.String())
+	}
+
+	assertion := "SSE expectation not met (" + reason + ")"
+	s.h.fail(s.h.formatError(assertion, strings.Join(expected, "; "), fmt.Sprintf("%d events received", len(s.Events()))))
+}
+
+// formatEvents lists the received events for error reports.
+func (s *SSEStream) formatEvents() string {
+	events := s.Events()
+	if len(events) == 0 {
+		return "Events:   (none)\n"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("Events:\n")
+
+	for i, event := range events {
+		fmt.Fprintf(&sb, "  #%d %s\n", i+1, event)
+	}
+
+	return sb.String()
+}
+
+// String formats the event for error reports.
+func (e SSEEvent) String() string {
+	s := fmt.Sprintf("event=%s data=%s", e.Type, e.Data)
+	if e.ID != "" {
+		s += " id=" + e.ID
+	}
+
+	if e.Retry > 0 {
+		s += " retry=" + e.Retry.String()
+	}
+
+	return s
+}
+
+// matches reports whether event satisfies the expectation.
+func (e sseExpectation) matches(event SSEEvent) bool {
+	if event.Type != e.eventType || !MatchText(event.Data, e.data) {
+		return false
+	}
+
+	if e.id != nil && event.ID != *e.id {
+		return false
+	}
+
+	return e.retry == 0 || event.Retry == e.retry
+}
+
+// String formats the expectation for error reports.
+func (e sseExpectation) String() string {
+	s := fmt.Sprintf("event=%s data=%s", e.eventType, DescribeMatcher(e.data))
+	if e.id != nil {
+		s += " id=" + *e.id
+	}
+
+	if e.retry > 0 {
+		s += " retry=" + e.retry.String()
+	}
+
+	return s
+}
+
+// sseReader parses an event stream in the background.
+type sseReader struct {
+	notify chan struct{}
+
+	mu          sync.Mutex
+	events      []SSEEvent
+	err         error
+	lastEventID string
+	retry       time.Duration
+}
+
+// newSSEReader starts parsing body.
+func newSSEReader(body io.Reader) *sseReader {
+	r := &sseReader{notify: make(chan struct{}, 1)}
+
+	go r.run(body)
+
+	return r
+}
+
+// snapshot returns the events received so far and the error that ended the
+// stream, if it has ended.
+func (r *sseReader) snapshot() ([]SSEEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.events, r.err
+}
+
+// resumeState returns the last event ID and reconnection time.
+func (r *sseReader) resumeState() (string, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.lastEventID, r.retry
+}
+
+// run parses body line by line following the event stream format.
+func (r *sseReader) run(body io.Reader) {
+	scanner := bufio.NewScanner(body)
+	scanner.Split(scanSSELines)
+
+	var (
+		event SSEEvent
+		data  []string
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			if len(data) > 0 {
+				event.Data = strings.Join(data, "\n")
+				r.dispatch(event)
+			}
+
+			event, data = SSEEvent{}, nil
+
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			event.Type = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			r.setLastEventID(value)
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				event.Retry = time.Duration(ms) * time.Millisecond
+				r.setRetry(event.Retry)
+			}
+		}
+	}
+
+	r.finish(scanner.Err())
+}
+
+// dispatch records a complete event.
+func (r *sseReader) dispatch(event SSEEvent) {
+	r.mu.Lock()
+
+	if event.Type == "" {
+		event.Type = "message"
+	}
+
+	event.ID = r.lastEventID
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+
+	r.signal()
+}
+
+func (r *sseReader) setLastEventID(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastEventID = id
+}
+
+func (r *sseReader) setRetry(retry time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.retry = retry
+}
+
+// finish records why the stream ended.
+func (r *sseReader) finish(err error) {
+	if err == nil {
+		err = io.EOF
+	}
+
+	r.mu.Lock()
+	r.err = err
+	r.mu.Unlock()
+
+	r.signal()
+}
+
+// signal wakes a waiting Within without blocking the parser.
+func (r *sseReader) signal() {
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// scanSSELines splits on CRLF, LF or CR line endings.
+func scanSSELines(data []byte, atEOF bool) (int, []byte, error) {
+	for i, b := range data {
+		switch b {
+		case '\n':
+			return i + 1, data[:i], nil
+		case '\r':
+			if i+1 < len(data) {
+				if data[i+1] == '\n' {
+					return i + 2, data[:i], nil
+				}
+
+				return i + 1, data[:i], nil
+			}
+
+			if atEOF {
+				return i + 1, data[:i], nil
+			}
+
+			// Wait for the next byte to tell CR from CRLF.
+			return 0, nil, nil
+		}
+	}
+
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
@@ -0,0 +1,158 @@
+package e2e_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+)
+
+// patternReader yields a repeating byte pattern without holding it in memory.
+type patternReader struct {
+	remaining int64
+	offset    int64
+}
+
+func (r *patternReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+
+	n := int64(len(p))
+	if n > r.remaining {
+		n = r.remaining
+	}
+
+	for i := range n {
+		p[i] = byte((r.offset + i) % 251)
+	}
+
+	r.offset += n
+	r.remaining -= n
+
+	return int(n), nil
+}
+
+func TestStreamingBodyAssertions(t *testing.T) {
+	const size = 64 << 20
+
+	want := sha256.New()
+	_, _ = io.Copy(want, &patternReader{remaining: size})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(size))
+		_, _ = io.Copy(w, &patternReader{remaining: size})
+	}))
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL, MaxCaptureSize: 1 << 10})
+
+	client.GET("/download").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectBodyLength(size).
+		ExpectSHA256(hex.EncodeToString(want.Sum(nil)))
+}
+
+func TestChecksum(t *testing.T) {
+	server := testServerWithBody("hello world")
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+	sum := sha512.Sum512([]byte("hello world"))
+
+	client.GET("/").
+		Checksum(crypto.SHA512).
+		Execute(t.Context()).
+		ExpectChecksum(crypto.SHA512, strings.ToUpper(hex.EncodeToString(sum[:]))).
+		ExpectBodyLength(11)
+}
+
+func TestRange(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "data.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	sum := sha256.Sum256(content[100:200])
+
+	client.GET("/data.bin").
+		Range(100, 199).
+		Execute(t.Context()).
+		ExpectRange().
+		ExpectHeader("Content-Range", "bytes 100-199/1000").
+		ExpectSHA256(hex.EncodeToString(sum[:]))
+
+	client.GET("/data.bin").
+		Range(990, -1).
+		Execute(t.Context()).
+		ExpectRange().
+		ExpectBodyLength(10)
+}
+
+func TestRangeIgnoredByServer(t *testing.T) {
+	server := testServerWithBody("full body")
+	defer server.Close()
+
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Range status mismatch") || !strings.Contains(mt.fatalMsg, "Expected: 206 Partial Content") {
+			t.Errorf("Expected range status mismatch, got:\n%s", mt.fatalMsg)
+		}
+	}()
+
+	client.GET("/").
+		Range(0, 3).
+		Execute(t.Context()).
+		ExpectRange()
+}
+
+func TestJSONOnTruncatedBody(t *testing.T) {
+	server := testServerWithBody(`{"items":["` + strings.Repeat("x", 4096) + `"]}`)
+	defer server.Close()
+
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "exceeds the capture limit of 1024 bytes") {
+			t.Errorf("Expected capture limit error, got:\n%s", mt.fatalMsg)
+		}
+	}()
+
+	client.GET("/").
+		MaxCapture(1024).
+		Execute(t.Context()).
+		ExpectBodyLength(4110).
+		ExpectJSON(`{"items":[]}`)
+}
+
+func testServerWithBody(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+}
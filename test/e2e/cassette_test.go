@@ -0,0 +1,299 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sivchari/e2e"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	var hits atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"method": r.Method,
+			"page":   r.URL.Query().Get("page"),
+			"body":   string(body),
+		})
+	}))
+
+	dir := t.TempDir()
+	config := &e2e.CassetteConfig{Dir: dir, Name: "users"}
+
+	t.Run("record", func(t *testing.T) {
+		t.Setenv("E2E_CASSETTE_MODE", "record")
+
+		client := e2e.New(t, e2e.Config{BaseURL: server.URL, Cassette: config})
+
+		client.GET("/users").
+			Query("page", "2").
+			Authorization("Bearer secret").
+			Execute(t.Context()).
+			ExpectJSON(`{"method":"GET","page":"2","body":""}`)
+
+		client.POST("/users").
+			Body(map[string]string{"name": "Alice"}).
+			Execute(t.Context()).
+			ExpectStatus(http.StatusCreated)
+	})
+
+	server.Close()
+
+	recorded := hits.Load()
+
+	data, err := os.ReadFile(filepath.Join(dir, "users.json"))
+	if err != nil {
+		t.Fatalf("Expected cassette to be written: %v", err)
+	}
+
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("Expected Authorization to be redacted in the cassette:\n%s", data)
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		t.Setenv("E2E_CASSETTE_MODE", "")
+
+		client := e2e.New(t, e2e.Config{BaseURL: server.URL, Cassette: config})
+
+		client.POST("/users").
+			Body(`{ "name": "Alice" }`).
+			Execute(t.Context()).
+			ExpectStatus(http.StatusCreated).
+			ExpectJSON(`{"method":"POST","page":"","body":"{\"name\":\"Alice\"}"}`)
+
+		client.GET("/users").
+			Query("page", "2").
+			Execute(t.Context()).
+			ExpectStatus(http.StatusCreated).
+			ExpectHeader("Content-Type", "application/json").
+			ExpectJSON(`{"method":"GET","page":"2","body":""}`)
+	})
+
+	if got := hits.Load(); got != recorded {
+		t.Errorf("Expected replay not to reach the server, got %d extra requests", got-recorded)
+	}
+}
+
+func TestCassetteMatchHeaders(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"tenant": r.Header.Get("X-Tenant")})
+	})
+
+	config := &e2e.CassetteConfig{
+		Dir:          t.TempDir(),
+		Name:         "tenants",
+		Match:        []e2e.MatchOn{e2e.MatchMethod, e2e.MatchPath},
+		MatchHeaders: []string{"X-Tenant"},
+	}
+
+	t.Run("record", func(t *testing.T) {
+		t.Setenv("E2E_CASSETTE_MODE", "record")
+
+		client := e2e.New(t, e2e.Config{Handler: handler, Cassette: config})
+
+		for _, tenant := range []string{"a", "b"} {
+			client.GET("/whoami").
+				Header("X-Tenant", tenant).
+				Execute(t.Context()).
+				ExpectJSON(map[string]string{"tenant": tenant})
+		}
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		t.Setenv("E2E_CASSETTE_MODE", "replay")
+
+		client := e2e.New(t, e2e.Config{BaseURL: "http://offline.invalid", Cassette: config})
+
+		client.GET("/whoami").
+			Query("ignored", "true").
+			Header("X-Tenant", "b").
+			Execute(t.Context()).
+			ExpectJSON(`{"tenant":"b"}`)
+
+		// Exhausted matches keep replaying the last one.
+		client.GET("/whoami").
+			Header("X-Tenant", "b").
+			Execute(t.Context()).
+			ExpectJSON(`{"tenant":"b"}`)
+	})
+}
+
+func TestCassetteUnmatchedRequest(t *testing.T) {
+	t.Setenv("E2E_CASSETTE_MODE", "replay")
+
+	dir := t.TempDir()
+	cassette := `{"interactions":[{"request":{"method":"GET","url":"http://example.com/users"},"response":{"status":200,"proto":"HTTP/1.1","body":"[]"}}]}`
+
+	if err := os.WriteFile(filepath.Join(dir, "users.json"), []byte(cassette), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{
+		BaseURL:  "http://example.com",
+		Cassette: &e2e.CassetteConfig{Dir: dir, Name: "users"},
+	})
+
+	client.GET("/users").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectJSON(`[]`)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		for _, want := range []string{
+			"no interaction in cassette",
+			"matches DELETE /users",
+			"E2E_CASSETTE_MODE=record",
+			"recorded: GET http://example.com/users",
+		} {
+			if !strings.Contains(mt.fatalMsg, want) {
+				t.Errorf("Expected error message to contain %q, got:\n%s", want, mt.fatalMsg)
+			}
+		}
+	}()
+
+	client.DELETE("/users").Execute(t.Context())
+}
+
+func TestCassetteRecordCapsBody(t *testing.T) {
+	t.Setenv("E2E_CASSETTE_MODE", "record")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("x", 1<<20)))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	t.Run("record", func(t *testing.T) {
+		client := e2e.New(t, e2e.Config{
+			BaseURL:        server.URL,
+			MaxCaptureSize: 1024,
+			Cassette:       &e2e.CassetteConfig{Dir: dir, Name: "download"},
+		})
+
+		client.GET("/download").
+			Execute(t.Context()).
+			ExpectStatus(http.StatusOK).
+			ExpectBodyLength(1 << 20)
+	})
+
+	data, err := os.ReadFile(filepath.Join(dir, "download.json"))
+	if err != nil {
+		t.Fatalf("Expected cassette to be written: %v", err)
+	}
+
+	var file struct {
+		Interactions []struct {
+			Response struct {
+				Body      string `json:"body"`
+				Truncated bool   `json:"truncated"`
+			} `json:"response"`
+		} `json:"interactions"`
+	}
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(file.Interactions) != 1 {
+		t.Fatalf("Expected 1 interaction, got %d", len(file.Interactions))
+	}
+
+	if resp := file.Interactions[0].Response; len(resp.Body) != 1024 || !resp.Truncated {
+		t.Errorf("Expected the body to be capped at 1024 bytes and marked truncated, got %d bytes (truncated=%v)",
+			len(resp.Body), resp.Truncated)
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		t.Setenv("E2E_CASSETTE_MODE", "replay")
+
+		mt := &mockT{TB: t}
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("Expected Fatal to be called")
+			}
+
+			want := "download.json has a body cut off at 1024 bytes by MaxCaptureSize"
+			if !strings.Contains(mt.fatalMsg, want) {
+				t.Errorf("Expected error message to contain %q, got:\n%s", want, mt.fatalMsg)
+			}
+		}()
+
+		client := e2e.New(mt, e2e.Config{
+			BaseURL:  server.URL,
+			Cassette: &e2e.CassetteConfig{Dir: dir, Name: "download"},
+		})
+
+		client.GET("/download").Execute(t.Context())
+	})
+}
+
+func TestCassetteStreamedRequestBody(t *testing.T) {
+	var received atomic.Int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := io.Copy(io.Discard, r.Body)
+		received.Store(n)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	upload := strings.Repeat("u", 64<<10)
+	config := e2e.Config{
+		BaseURL:        server.URL,
+		MaxCaptureSize: 1024,
+		Cassette:       &e2e.CassetteConfig{Dir: dir, Name: "upload"},
+	}
+
+	t.Run("record", func(t *testing.T) {
+		t.Setenv("E2E_CASSETTE_MODE", "record")
+
+		e2e.New(t, config).POST("/upload").
+			Body(strings.NewReader(upload)).
+			Execute(t.Context()).
+			ExpectStatus(http.StatusAccepted)
+	})
+
+	if got := received.Load(); got != int64(len(upload)) {
+		t.Errorf("Expected the whole body to be sent, server received %d bytes", got)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "upload.json"))
+	if err != nil {
+		t.Fatalf("Expected cassette to be written: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"body": "`+upload[:1024]+`"`) {
+		t.Errorf("Expected the recorded request body to be capped at 1024 bytes:\n%.200s", data)
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		t.Setenv("E2E_CASSETTE_MODE", "replay")
+
+		e2e.New(t, config).POST("/upload").
+			Body(strings.NewReader(upload)).
+			Execute(t.Context()).
+			ExpectStatus(http.StatusAccepted)
+	})
+}
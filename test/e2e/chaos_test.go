@@ -0,0 +1,173 @@
+package e2e_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+	"github.com/sivchari/e2e/test/e2e/testserver"
+)
+
+func newChaosProxy(t *testing.T) *testserver.ChaosProxy {
+	t.Helper()
+
+	server := testserver.NewEchoServer()
+	t.Cleanup(server.Close)
+
+	proxy := testserver.NewChaosProxy(server.URL)
+	t.Cleanup(proxy.Close)
+
+	return proxy
+}
+
+func TestChaosProxyForwards(t *testing.T) {
+	proxy := newChaosProxy(t)
+	client := e2e.New(t, e2e.Config{BaseURL: proxy.URL})
+
+	client.POST("/users").
+		Body(map[string]string{"name": "Alice"}).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectJSON(`{"method":"POST","path":"/users","body":{"name":"Alice"}}`)
+}
+
+func TestChaosProxyStatusIsRetried(t *testing.T) {
+	proxy := newChaosProxy(t)
+	rule := proxy.Inject(testserver.Status(http.StatusServiceUnavailable)).
+		Method(http.MethodGet).
+		Path("/users/*").
+		Times(2)
+
+	client := e2e.New(t, e2e.Config{
+		BaseURL: proxy.URL,
+		Retry:   &e2e.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})
+
+	client.GET("/users/1").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK)
+
+	if got := rule.Hits(); got != 2 {
+		t.Errorf("Expected the fault to be injected twice, got %d", got)
+	}
+}
+
+func TestChaosProxyLatencyAndBandwidth(t *testing.T) {
+	proxy := newChaosProxy(t)
+	proxy.Inject(testserver.Latency(100 * time.Millisecond)).Path("/slow")
+	proxy.Inject(testserver.Bandwidth(1000)).Path("/drip")
+
+	client := e2e.New(t, e2e.Config{BaseURL: proxy.URL})
+
+	for _, path := range []string{"/slow", "/drip"} {
+		start := time.Now()
+
+		client.POST(path).
+			Body(map[string]string{"padding": strings.Repeat("x", 200)}).
+			Execute(t.Context()).
+			ExpectStatus(http.StatusOK).
+			ExpectBodyLength(int64(len(`{"body":{"padding":"`) + 200 + len(`"},"method":"POST","path":"`) + len(path) + len(`"}`) + 1))
+
+		if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+			t.Errorf("Expected %s to take at least 100ms, took %v", path, elapsed)
+		}
+	}
+}
+
+func TestChaosProxyTimeout(t *testing.T) {
+	proxy := newChaosProxy(t)
+	proxy.Inject(testserver.Latency(time.Second))
+
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{BaseURL: proxy.URL, Timeout: 50 * time.Millisecond})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "context deadline exceeded") {
+			t.Errorf("Expected a timeout, got:\n%s", mt.fatalMsg)
+		}
+	}()
+
+	client.GET("/").Execute(t.Context())
+}
+
+func TestChaosProxyDrop(t *testing.T) {
+	proxy := newChaosProxy(t)
+	proxy.Inject(testserver.Drop())
+
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{BaseURL: proxy.URL})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "failed to execute GET request") {
+			t.Errorf("Expected a transport error, got:\n%s", mt.fatalMsg)
+		}
+	}()
+
+	client.GET("/").Execute(t.Context())
+}
+
+func TestChaosProxyTruncate(t *testing.T) {
+	proxy := newChaosProxy(t)
+	proxy.Inject(testserver.Truncate(10))
+
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{BaseURL: proxy.URL})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Failed to read response body") {
+			t.Errorf("Expected a body read error, got:\n%s", mt.fatalMsg)
+		}
+	}()
+
+	client.GET("/").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectJSON(`{"method":"GET","path":"/"}`)
+}
+
+func TestChaosProxyProbability(t *testing.T) {
+	var forwarded atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		forwarded.Add(1)
+	}))
+	defer server.Close()
+
+	proxy := testserver.NewChaosProxy(server.URL)
+	defer proxy.Close()
+
+	proxy.Seed(42)
+	rule := proxy.Inject(testserver.Status(http.StatusInternalServerError)).Probability(0.5)
+
+	client := e2e.New(t, e2e.Config{BaseURL: proxy.URL})
+
+	for range 40 {
+		client.GET("/").Execute(t.Context())
+	}
+
+	if injected := rule.Hits(); injected == 0 || injected == 40 || injected+int(forwarded.Load()) != 40 {
+		t.Errorf("Expected about half of 40 requests to fail, %d failed and %d were forwarded", injected, forwarded.Load())
+	}
+
+	proxy.Reset()
+
+	client.GET("/").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK)
+}
@@ -0,0 +1,97 @@
+package e2e_test
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+	"github.com/sivchari/e2e/test/e2e/testserver"
+)
+
+// countingTransport counts round trips before delegating to the default transport.
+type countingTransport struct {
+	count atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.count.Add(1)
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCustomTransport(t *testing.T) {
+	server := testserver.NewEchoServer()
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL, Transport: transport})
+
+	client.GET("/test").
+		Execute(t.Context()).
+		ExpectStatus(200)
+
+	// A per-request timeout must not bypass the configured transport.
+	client.GET("/test").
+		Timeout(5 * time.Second).
+		Execute(t.Context()).
+		ExpectStatus(200)
+
+	if got := transport.count.Load(); got != 2 {
+		t.Errorf("Expected 2 round trips through custom transport, got %d", got)
+	}
+}
+
+func TestCustomClient(t *testing.T) {
+	server := testserver.NewEchoServer()
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client := e2e.New(t, e2e.Config{
+		BaseURL: server.URL,
+		Client:  &http.Client{Transport: transport},
+	})
+
+	client.POST("/test").
+		Body(map[string]string{"name": "Alice"}).
+		Timeout(5 * time.Second).
+		Execute(t.Context()).
+		ExpectStatus(200)
+
+	if got := transport.count.Load(); got != 1 {
+		t.Errorf("Expected 1 round trip through custom client, got %d", got)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	server := testserver.NewEchoServer()
+	defer server.Close()
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+	}()
+
+	client := e2e.New(mt, e2e.Config{
+		BaseURL: server.URL,
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+
+			return nil, req.Context().Err()
+		}),
+	})
+	client.GET("/test").
+		Timeout(10 * time.Millisecond).
+		Execute(t.Context())
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,200 @@
+package e2e_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/sivchari/e2e"
+)
+
+// newCompressionServer decodes the request body according to its
+// Content-Encoding and replies with it, encoded with the coding named by
+// the encoding query parameter.
+func newCompressionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := decodeBody(r.Header.Get("Content-Encoding"), r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		payload, _ := json.Marshal(map[string]string{
+			"received":        string(body),
+			"accept_encoding": r.Header.Get("Accept-Encoding"),
+		})
+
+		encoding := r.URL.Query().Get("encoding")
+		if encoding != "" {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodHead {
+			return
+		}
+
+		enc := encodeWriter(encoding, w)
+		_, _ = enc.Write(payload)
+		_ = enc.Close()
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func decodeBody(encoding string, r io.Reader) ([]byte, error) {
+	switch encoding {
+	case "gzip":
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return io.ReadAll(zr)
+	case "deflate":
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return io.ReadAll(zr)
+	case "br":
+		return io.ReadAll(brotli.NewReader(r))
+	case "zstd":
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		return io.ReadAll(zr)
+	default:
+		return io.ReadAll(r)
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func encodeWriter(encoding string, w io.Writer) io.WriteCloser {
+	switch encoding {
+	case "gzip":
+		return gzip.NewWriter(w)
+	case "deflate":
+		return zlib.NewWriter(w)
+	case "br":
+		return brotli.NewWriter(w)
+	case "zstd":
+		zw, _ := zstd.NewWriter(w)
+
+		return zw
+	default:
+		return nopWriteCloser{w}
+	}
+}
+
+func TestCompression(t *testing.T) {
+	server := newCompressionServer(t)
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	for _, encoding := range []e2e.Encoding{e2e.EncodingGzip, e2e.EncodingDeflate, e2e.EncodingBrotli, e2e.EncodingZstd} {
+		t.Run(string(encoding), func(t *testing.T) {
+			client.POST("/").
+				Query("encoding", string(encoding)).
+				Compress(encoding).
+				Body(map[string]string{"name": "Alice"}).
+				Execute(t.Context()).
+				ExpectStatus(http.StatusOK).
+				ExpectContentEncoding(encoding).
+				ExpectJSON(map[string]string{
+					"received":        `{"name":"Alice"}`,
+					"accept_encoding": "gzip, deflate, br, zstd",
+				})
+		})
+	}
+}
+
+func TestCompressionIdentity(t *testing.T) {
+	server := newCompressionServer(t)
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	client.GET("/").
+		Header("Accept-Encoding", "identity").
+		Execute(t.Context()).
+		ExpectContentEncoding(e2e.EncodingIdentity).
+		ExpectJSON(`{"received":"","accept_encoding":"identity"}`)
+
+	client.HEAD("/").
+		Query("encoding", "gzip").
+		Execute(t.Context()).
+		ExpectContentEncoding(e2e.EncodingGzip).
+		ExpectBodyLength(0)
+}
+
+func TestDecompressionOptOut(t *testing.T) {
+	server := newCompressionServer(t)
+
+	var want bytes.Buffer
+
+	zw := gzip.NewWriter(&want)
+	_, _ = zw.Write([]byte(`{"accept_encoding":"gzip, deflate, br, zstd","received":""}`))
+	_ = zw.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL, DisableDecompression: true})
+
+	client.GET("/").
+		Query("encoding", "gzip").
+		Execute(t.Context()).
+		ExpectContentEncoding(e2e.EncodingGzip).
+		ExpectBodyLength(int64(want.Len()))
+
+	client.GET("/").
+		Query("encoding", "gzip").
+		Decompress(true).
+		Execute(t.Context()).
+		ExpectJSON(`{"received":"","accept_encoding":"gzip, deflate, br, zstd"}`)
+}
+
+func TestContentEncodingMismatch(t *testing.T) {
+	server := newCompressionServer(t)
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		for _, want := range []string{
+			"Encoding: br, ",
+			"Content-Encoding mismatch:",
+			"Expected: gzip",
+			"Actual:   (none)",
+		} {
+			if !strings.Contains(mt.fatalMsg, want) {
+				t.Errorf("Expected error message to contain %q, got:\n%s", want, mt.fatalMsg)
+			}
+		}
+	}()
+
+	client.POST("/").
+		Compress(e2e.EncodingBrotli).
+		Body(`{"a":1}`).
+		Execute(t.Context()).
+		ExpectContentEncoding(e2e.EncodingGzip)
+}
@@ -0,0 +1,48 @@
+package e2e_test
+
+import (
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sivchari/e2e"
+)
+
+func TestProcessCoverage(t *testing.T) {
+	profile := filepath.Join(t.TempDir(), "e2e.cover.out")
+
+	process := e2e.StartProcess(t, "./testdata/server", e2e.ProcessOptions{
+		Build:    true,
+		Args:     []string{"-addr", "127.0.0.1:{{port}}"},
+		Ready:    e2e.ReadinessProbe{TCP: true},
+		Coverage: &e2e.CoverageOptions{Profile: profile, Mode: "atomic"},
+	})
+
+	process.New(t, e2e.Config{}).
+		GET("/hello").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK)
+
+	process.Stop()
+
+	data, err := os.ReadFile(profile)
+	if err != nil {
+		t.Fatalf("Expected a coverage profile: %v", err)
+	}
+
+	if !strings.HasPrefix(string(data), "mode: atomic\n") {
+		t.Errorf("Expected an atomic text profile, got:\n%s", data)
+	}
+
+	out, err := exec.CommandContext(t.Context(), "go", "tool", "cover", "-func="+profile).CombinedOutput()
+	if err != nil {
+		t.Fatalf("go tool cover failed: %v\n%s", err, out)
+	}
+
+	if !strings.Contains(string(out), "testdata/server/main.go") || !strings.Contains(string(out), "total:") {
+		t.Errorf("Expected coverage of the server, got:\n%s", out)
+	}
+}
@@ -0,0 +1,160 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+	"github.com/sivchari/e2e/test/e2e/testserver"
+)
+
+func TestCurlReproduceCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		for _, want := range []string{
+			"Reproduce:\n  curl \\\n    --request POST \\\n",
+			"    --compressed \\\n",
+			"    -H 'Authorization: [REDACTED]' \\\n",
+			"    -H 'Content-Type: application/json' \\\n",
+			"    -H 'X-Trace: abc' \\\n",
+			`    --data-binary '{"name":"O'\''Brien"}' \` + "\n",
+			"    '" + server.URL + "/users?page=2'\n",
+		} {
+			if !strings.Contains(mt.fatalMsg, want) {
+				t.Errorf("Expected error message to contain %q, got:\n%s", want, mt.fatalMsg)
+			}
+		}
+
+		if _, reproduce, _ := strings.Cut(mt.fatalMsg, "Reproduce:"); strings.Contains(reproduce, "secret") {
+			t.Errorf("Expected Authorization to be redacted in the curl command, got:\n%s", mt.fatalMsg)
+		}
+	}()
+
+	client.POST("/users").
+		Query("page", "2").
+		Authorization("Bearer secret").
+		Header("X-Trace", "abc").
+		Body(map[string]string{"name": "O'Brien"}).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusCreated)
+}
+
+func TestCurlReproduceUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "echo.sock")
+
+	server := testserver.NewUnixEchoServer(socketPath)
+	defer server.Close()
+
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{
+		BaseURL:  "http://sidecar.local",
+		Target:   "unix://" + socketPath,
+		Protocol: e2e.ProtocolHTTP1,
+	})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		for _, want := range []string{
+			"    --http1.1 \\\n",
+			"    --unix-socket '" + socketPath + "' \\\n",
+			"    'http://sidecar.local/health'\n",
+		} {
+			if !strings.Contains(mt.fatalMsg, want) {
+				t.Errorf("Expected error message to contain %q, got:\n%s", want, mt.fatalMsg)
+			}
+		}
+	}()
+
+	client.GET("/health").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusNoContent)
+}
+
+func TestCurlScript(t *testing.T) {
+	if _, err := exec.LookPath("curl"); err != nil {
+		t.Skip("curl is not installed")
+	}
+
+	if _, err := exec.LookPath("gzip"); err != nil {
+		t.Skip("gzip is not installed")
+	}
+
+	server := newCompressionServer(t)
+	dir := t.TempDir()
+
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL, CurlDir: dir})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		script := filepath.Join(dir, regexp.MustCompile(`[^A-Za-z0-9._-]+`).ReplaceAllString(t.Name(), "_")+".sh")
+		if !strings.Contains(mt.fatalMsg, "Script:   "+script) {
+			t.Errorf("Expected error message to name the script, got:\n%s", mt.fatalMsg)
+		}
+
+		if _, err := os.Stat(script); err != nil {
+			t.Fatalf("Expected script to be written: %v", err)
+		}
+
+		out, err := exec.CommandContext(t.Context(), "bash", script).Output()
+		if err != nil {
+			t.Fatalf("Failed to run script: %v", err)
+		}
+
+		var got map[string]string
+		if err := json.Unmarshal(out, &got); err != nil {
+			t.Fatalf("Failed to decode script output %q: %v", out, err)
+		}
+
+		if got["received"] != `{"name":"Alice"}` {
+			t.Errorf("Expected the script to send the same body, server received %q", got["received"])
+		}
+	}()
+
+	client.POST("/").
+		Query("encoding", "gzip").
+		Compress(e2e.EncodingGzip).
+		Body(map[string]string{"name": "Alice"}).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusAccepted)
+}
+
+func TestCurlScriptOnlyOnFailure(t *testing.T) {
+	server, _ := newFlakyServer(t, 2, http.StatusServiceUnavailable)
+	dir := t.TempDir()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL, CurlDir: dir})
+	client.GET("/").
+		Eventually(5*time.Second, time.Millisecond).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK)
+
+	if entries, _ := os.ReadDir(dir); len(entries) > 0 {
+		t.Errorf("Expected no script for a passing test, found %s", entries[0].Name())
+	}
+}
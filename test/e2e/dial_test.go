@@ -0,0 +1,61 @@
+package e2e_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/sivchari/e2e"
+	"github.com/sivchari/e2e/test/e2e/testserver"
+)
+
+func TestUnixSocketTarget(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "echo.sock")
+
+	server := testserver.NewUnixEchoServer(socketPath)
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{
+		BaseURL: "http://sidecar.local/api/",
+		Target:  "unix://" + socketPath,
+	})
+
+	client.GET("users").
+		Query("page", "2").
+		Execute(t.Context()).
+		ExpectStatus(200).
+		ExpectJSON(map[string]interface{}{
+			"method": "GET",
+			"path":   "/api/users",
+			"query": map[string]interface{}{
+				"page": []interface{}{"2"},
+			},
+		})
+}
+
+func TestCustomDialContext(t *testing.T) {
+	server := testserver.NewEchoServer()
+	defer server.Close()
+
+	var dialed []string
+
+	client := e2e.New(t, e2e.Config{
+		BaseURL: "http://orders.internal",
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dialed = append(dialed, addr)
+
+			var d net.Dialer
+
+			return d.DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	})
+
+	client.GET("/orders").
+		Execute(t.Context()).
+		ExpectStatus(200)
+
+	if len(dialed) != 1 || dialed[0] != "orders.internal:80" {
+		t.Errorf("Expected a single dial to orders.internal:80, got %v", dialed)
+	}
+}
@@ -0,0 +1,117 @@
+package e2e_test
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+	"github.com/sivchari/e2e/test/e2e/testserver"
+)
+
+func TestEchoServerRawBody(t *testing.T) {
+	server := testserver.NewEchoServer()
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	client.POST("/upload").
+		Body("plain text").
+		Execute(t.Context()).
+		ExpectJSON(map[string]interface{}{
+			"method":  "POST",
+			"path":    "/upload",
+			"rawBody": base64.StdEncoding.EncodeToString([]byte("plain text")),
+		}).
+		ExpectHeader("X-Echo-Meta-Body-Length", "10").
+		ExpectHeader("X-Echo-Meta-Content-Length", "10")
+
+	client.POST("/upload").
+		Query("raw", "true").
+		Body(`{"name": "Alice"}`).
+		Execute(t.Context()).
+		ExpectJSON(map[string]interface{}{
+			"method":  "POST",
+			"path":    "/upload",
+			"query":   map[string]interface{}{"raw": []string{"true"}},
+			"body":    map[string]string{"name": "Alice"},
+			"rawBody": base64.StdEncoding.EncodeToString([]byte(`{"name": "Alice"}`)),
+		})
+}
+
+func TestEchoServerMetadata(t *testing.T) {
+	server := testserver.NewEchoServer()
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	client.PUT("/stream").
+		Body(strings.NewReader("streamed")).
+		Execute(t.Context()).
+		ExpectHeader("X-Echo-Meta-Proto", "HTTP/1.1").
+		ExpectHeader("X-Echo-Meta-Host", strings.TrimPrefix(server.URL, "http://")).
+		ExpectHeader("X-Echo-Meta-Tls", "none").
+		ExpectHeader("X-Echo-Meta-Content-Length", "-1").
+		ExpectHeader("X-Echo-Meta-Transfer-Encoding", "chunked").
+		ExpectHeader("X-Echo-Meta-Body-Length", "8")
+
+	tlsServer := testserver.NewTLSEchoServer()
+	defer tlsServer.Close()
+
+	tlsClient := e2e.New(t, e2e.Config{BaseURL: tlsServer.URL, Client: tlsServer.Client()})
+
+	tlsClient.GET("/").
+		Execute(t.Context()).
+		ExpectHeader("X-Echo-Meta-Tls", "TLS 1.3")
+}
+
+func TestEchoServerAllHeaderValues(t *testing.T) {
+	server := testserver.NewEchoServer()
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Add("X-Multi", "one")
+	req.Header.Add("X-Multi", "two")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if got := strings.Join(resp.Header.Values("X-Echo-X-Multi"), ","); got != "one,two" {
+		t.Errorf("Expected both header values to be echoed, got %q", got)
+	}
+}
+
+func TestEchoServerKnobs(t *testing.T) {
+	server := testserver.NewEchoServer()
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	start := time.Now()
+
+	client.GET("/teapot").
+		Query("status", "418").
+		Query("delay", "50ms").
+		Query("header", "X-Brewed: yes").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusTeapot).
+		ExpectHeader("X-Brewed", "yes")
+
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected the response to be delayed, took %v", elapsed)
+	}
+
+	client.GET("/").
+		Query("status", "abc").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusBadRequest)
+}
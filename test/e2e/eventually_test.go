@@ -0,0 +1,136 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+)
+
+// newJobServer returns a server whose job reports "done" after ready polls.
+func newJobServer(t *testing.T, ready int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var polls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if polls.Add(1) < ready {
+			_, _ = w.Write([]byte(`{"status":"running"}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"status":"done"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &polls
+}
+
+func TestEventually(t *testing.T) {
+	server, polls := newJobServer(t, 3)
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+	client.GET("/jobs/42").
+		Eventually(5*time.Second, 10*time.Millisecond).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectJSON(`{"status":"done"}`)
+
+	if got := polls.Load(); got != 3 {
+		t.Errorf("Expected 3 polls, got %d", got)
+	}
+}
+
+func TestEventuallyTimeout(t *testing.T) {
+	server, _ := newJobServer(t, 1000)
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Eventually: condition not met after") {
+			t.Errorf("Error message should contain attempt count, got: %s", mt.fatalMsg)
+		}
+
+		if !strings.Contains(mt.fatalMsg, "JSON response mismatch") {
+			t.Errorf("Error message should contain the last failure, got: %s", mt.fatalMsg)
+		}
+
+		if !strings.Contains(mt.fatalMsg, `"status": "running"`) {
+			t.Errorf("Error message should contain the last response, got: %s", mt.fatalMsg)
+		}
+	}()
+
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+	client.GET("/jobs/42").
+		Eventually(100*time.Millisecond, 10*time.Millisecond).
+		Execute(context.Background()).
+		ExpectJSON(`{"status":"done"}`)
+}
+
+func TestEventuallyContextCanceled(t *testing.T) {
+	server, _ := newJobServer(t, 1000)
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "context canceled") {
+			t.Errorf("Error message should mention cancellation, got: %s", mt.fatalMsg)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+	client.GET("/jobs/42").
+		Eventually(time.Minute, 10*time.Millisecond).
+		Execute(ctx).
+		ExpectJSON(`{"status":"done"}`)
+}
+
+// cleanupCounter counts the cleanups registered on the wrapped test.
+type cleanupCounter struct {
+	testing.TB
+	cleanups int
+}
+
+func (c *cleanupCounter) Cleanup(f func()) {
+	c.cleanups++
+	c.TB.Cleanup(f)
+}
+
+func TestEventuallyReleasesEachPoll(t *testing.T) {
+	server, polls := newJobServer(t, 20)
+
+	ct := &cleanupCounter{TB: t}
+
+	client := e2e.New(ct, e2e.Config{BaseURL: server.URL})
+	client.GET("/jobs/42").
+		Eventually(5*time.Second, time.Millisecond).
+		Execute(t.Context()).
+		ExpectJSON(`{"status":"done"}`)
+
+	if got := polls.Load(); got != 20 {
+		t.Errorf("Expected 20 polls, got %d", got)
+	}
+
+	if ct.cleanups > 2 {
+		t.Errorf("Expected cleanups to be registered once per request, got %d for 20 polls", ct.cleanups)
+	}
+}
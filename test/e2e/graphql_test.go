@@ -0,0 +1,149 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sivchari/e2e"
+)
+
+// newGraphQLServer answers operations by name: GetUser returns data and
+// MissingUser returns a partial response with an error, both with 200 OK.
+func newGraphQLServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query         string                 `json:"query"`
+			Variables     map[string]interface{} `json:"variables"`
+			OperationName string                 `json:"operationName"`
+		}
+
+		if r.URL.Path != "/graphql" || r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&req) != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch req.OperationName {
+		case "GetUser":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"user": map[string]interface{}{
+						"id":    req.Variables["id"],
+						"name":  "Alice",
+						"posts": []map[string]string{{"title": "Hello"}, {"title": "World"}},
+					},
+				},
+			})
+		default:
+			_, _ = w.Write([]byte(`{"data":{"user":null},"errors":[{"message":"user not found","path":["user"],"extensions":{"code":"NOT_FOUND"}}]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestGraphQLData(t *testing.T) {
+	server := newGraphQLServer(t)
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	client.GraphQL(`query GetUser($id: ID!) { user(id: $id) { id name posts { title } } }`).
+		Variables(map[string]string{"id": "1"}).
+		OperationName("GetUser").
+		Authorization("Bearer token").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectNoGraphQLErrors().
+		ExpectData("user.name", "Alice").
+		ExpectData("user.posts.1.title", "World").
+		ExpectData("user", `{"id":"1","name":"Alice","posts":[{"title":"Hello"},{"title":"World"}]}`)
+}
+
+func TestGraphQLError(t *testing.T) {
+	server := newGraphQLServer(t)
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	client.GraphQL(`query MissingUser { user(id: "0") { name } }`).
+		OperationName("MissingUser").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectGraphQLError("NOT_FOUND", "user").
+		ExpectGraphQLError("", "").
+		ExpectData("user", nil)
+}
+
+func TestGraphQLCustomPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/query" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":{"ok":true}}`))
+	}))
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL, GraphQLPath: "/api/query"})
+
+	client.GraphQL(`{ ok }`).
+		Execute(t.Context()).
+		ExpectData("ok", true)
+}
+
+func TestGraphQLErrorSummary(t *testing.T) {
+	server := newGraphQLServer(t)
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		for _, want := range []string{
+			"=== HTTP Request Failed ===",
+			"Response: 200 OK",
+			"GraphQL Errors:",
+			"#1 [NOT_FOUND] user: user not found",
+			"GraphQL errors present:",
+		} {
+			if !strings.Contains(mt.fatalMsg, want) {
+				t.Errorf("Expected error message to contain %q, got:\n%s", want, mt.fatalMsg)
+			}
+		}
+	}()
+
+	client.GraphQL(`query MissingUser { user(id: "0") { name } }`).
+		OperationName("MissingUser").
+		Execute(t.Context()).
+		ExpectNoGraphQLErrors()
+}
+
+func TestGraphQLMissingDataPath(t *testing.T) {
+	server := newGraphQLServer(t)
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Data mismatch (user.email)") || !strings.Contains(mt.fatalMsg, "Actual:   <missing>") {
+			t.Errorf("Expected missing data path in error message, got:\n%s", mt.fatalMsg)
+		}
+	}()
+
+	client.GraphQL(`query GetUser { user(id: "1") { name } }`).
+		OperationName("GetUser").
+		Execute(t.Context()).
+		ExpectData("user.email", "alice@example.com")
+}
@@ -0,0 +1,304 @@
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/sivchari/e2e"
+)
+
+// startGRPCServer serves srv on an in-memory listener and returns a config
+// dialing it.
+func startGRPCServer(t *testing.T, srv *grpc.Server) e2e.Config {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	t.Cleanup(srv.Stop)
+
+	return e2e.Config{
+		BaseURL: "http://bufnet",
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		},
+	}
+}
+
+// echoRequestID copies the x-request-id metadata entry into the trailer.
+func echoRequestID(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		_ = grpc.SetTrailer(ctx, metadata.Pairs("x-request-id", strings.Join(md.Get("x-request-id"), ",")))
+	}
+
+	return handler(ctx, req)
+}
+
+func newHealthServer() *grpc.Server {
+	srv := grpc.NewServer(grpc.UnaryInterceptor(echoRequestID))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("users", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthServer)
+
+	return srv
+}
+
+func TestGRPCHealthCheck(t *testing.T) {
+	client := e2e.New(t, startGRPCServer(t, newHealthServer()))
+
+	client.GRPC("grpc.health.v1.Health", "Check").
+		Message(&healthpb.HealthCheckRequest{Service: "users"}).
+		Metadata("x-request-id", "req-1").
+		Timeout(5*time.Second).
+		Execute(t.Context()).
+		ExpectCode(codes.OK).
+		ExpectMessage(`{"status":"SERVING"}`).
+		ExpectTrailer("x-request-id", "req-1")
+
+	client.GRPC("grpc.health.v1.Health", "Check").
+		Message(map[string]string{"service": "unknown"}).
+		Execute(t.Context()).
+		ExpectCode(codes.NotFound)
+}
+
+func TestGRPCResponseAccessor(t *testing.T) {
+	client := e2e.New(t, startGRPCServer(t, newHealthServer()))
+
+	resp := client.GRPC("grpc.health.v1.Health", "Check").
+		Message(`{"service":"users"}`).
+		Execute(t.Context()).
+		Response()
+
+	status := resp.ProtoReflect().Get(resp.ProtoReflect().Descriptor().Fields().ByName("status")).Enum()
+	if status != protoreflect.EnumNumber(healthpb.HealthCheckResponse_SERVING) {
+		t.Errorf("Expected SERVING, got %v", status)
+	}
+}
+
+func TestGRPCErrorMessage(t *testing.T) {
+	config := startGRPCServer(t, newHealthServer())
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, config)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		for _, want := range []string{
+			"=== gRPC Request Failed ===",
+			"Request:  grpc.health.v1.Health/Check",
+			`Message:  {"service":"unknown"}`,
+			"Status:   NotFound (unknown service)",
+			"Status code mismatch:",
+			"Expected: OK",
+		} {
+			if !strings.Contains(mt.fatalMsg, want) {
+				t.Errorf("Expected error message to contain %q, got:\n%s", want, mt.fatalMsg)
+			}
+		}
+	}()
+
+	client.GRPC("grpc.health.v1.Health", "Check").
+		Message(`{"service":"unknown"}`).
+		Execute(t.Context()).
+		ExpectCode(codes.OK)
+}
+
+// echoFile describes test.echo.v1.Echo, a service without generated code.
+func echoFile(t *testing.T) *descriptorpb.FileDescriptorProto {
+	t.Helper()
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/echo.proto"),
+		Package:    proto.String("test.echo.v1"),
+		Dependency: []string{"google/protobuf/wrappers.proto"},
+		Syntax:     proto.String("proto3"),
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Echo"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("Say"),
+				InputType:  proto.String(".google.protobuf.StringValue"),
+				OutputType: proto.String(".google.protobuf.StringValue"),
+			}},
+		}},
+	}
+}
+
+// newEchoServer serves test.echo.v1.Echo, optionally with reflection backed
+// by files.
+func newEchoServer(files *protoregistry.Files) *grpc.Server {
+	srv := grpc.NewServer()
+
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.echo.v1.Echo",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Say",
+			Handler: func(_ any, _ context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				in := new(wrapperspb.StringValue)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+
+				return wrapperspb.String("echo: " + in.GetValue()), nil
+			},
+		}},
+	}, struct{}{})
+
+	if files != nil {
+		reflectionpb.RegisterServerReflectionServer(srv, reflection.NewServerV1(reflection.ServerOptions{
+			Services:           srv,
+			DescriptorResolver: files,
+		}))
+	}
+
+	return srv
+}
+
+func TestGRPCReflection(t *testing.T) {
+	fd, err := protodesc.NewFile(echoFile(t), protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := new(protoregistry.Files)
+	for _, file := range []protoreflect.FileDescriptor{fd, wrapperspb.File_google_protobuf_wrappers_proto} {
+		if err := files.RegisterFile(file); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := e2e.New(t, startGRPCServer(t, newEchoServer(files)))
+
+	client.GRPC("test.echo.v1.Echo", "Say").
+		Message(`"hi"`).
+		Execute(t.Context()).
+		ExpectCode(codes.OK).
+		ExpectMessage(`"echo: hi"`)
+}
+
+func TestGRPCDescriptorSet(t *testing.T) {
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(wrapperspb.File_google_protobuf_wrappers_proto),
+			echoFile(t),
+		},
+	}
+
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "echo.pb")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := startGRPCServer(t, newEchoServer(nil))
+	config.GRPC = &e2e.GRPCConfig{
+		DescriptorSets:    []string{path},
+		DisableReflection: true,
+	}
+
+	client := e2e.New(t, config)
+
+	client.GRPC("test.echo.v1.Echo", "Say").
+		Message(`"descriptor"`).
+		Execute(t.Context()).
+		ExpectCode(codes.OK).
+		ExpectMessage(`"echo: descriptor"`)
+}
+
+// grpcWebHealthHandler answers grpc.health.v1.Health/Check in gRPC-Web format.
+func grpcWebHealthHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/grpc.health.v1.Health/Check" || r.Header.Get("Content-Type") != "application/grpc-web+proto" {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	req := new(healthpb.HealthCheckRequest)
+	if len(body) < 5 || proto.Unmarshal(body[5:], req) != nil {
+		http.Error(w, "bad frame", http.StatusBadRequest)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc-web+proto")
+	w.Header().Set("X-Served-By", "web")
+
+	if req.GetService() != "users" {
+		w.Header().Set("Grpc-Status", "5")
+		w.Header().Set("Grpc-Message", "unknown%20service")
+
+		return
+	}
+
+	payload, _ := proto.Marshal(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+	trailer := "grpc-status: 0\r\nx-request-id: " + r.Header.Get("X-Request-Id") + "\r\n"
+
+	var buf bytes.Buffer
+
+	for _, frame := range []struct {
+		flag byte
+		data []byte
+	}{{0x00, payload}, {0x80, []byte(trailer)}} {
+		buf.WriteByte(frame.flag)
+		_ = binary.Write(&buf, binary.BigEndian, uint32(len(frame.data))) //nolint:gosec // Test payloads are tiny.
+		buf.Write(frame.data)
+	}
+
+	_, _ = w.Write(buf.Bytes())
+}
+
+func TestGRPCWeb(t *testing.T) {
+	client := e2e.NewWithHandler(t, http.HandlerFunc(grpcWebHealthHandler))
+
+	client.GRPC("grpc.health.v1.Health", "Check").
+		Web().
+		Message(`{"service":"users"}`).
+		Metadata("x-request-id", "web-1").
+		Execute(t.Context()).
+		ExpectCode(codes.OK).
+		ExpectHeader("x-served-by", "web").
+		ExpectMessage(`{"status":"SERVING"}`).
+		ExpectTrailer("x-request-id", "web-1")
+
+	client.GRPC("grpc.health.v1.Health", "Check").
+		Web().
+		Message(`{"service":"unknown"}`).
+		Execute(t.Context()).
+		ExpectCode(codes.NotFound)
+}
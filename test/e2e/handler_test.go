@@ -0,0 +1,87 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sivchari/e2e"
+)
+
+func newUserHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-ID", r.Header.Get("X-Request-ID"))
+		_, _ = w.Write([]byte(`{"id":"` + r.PathValue("id") + `","host":"` + r.Host + `"}`))
+	})
+	mux.HandleFunc("POST /users", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"validation failed"}`))
+	})
+
+	return mux
+}
+
+func TestHandlerMode(t *testing.T) {
+	t.Parallel()
+
+	client := e2e.NewWithHandler(t, newUserHandler())
+
+	client.GET("/users/42").
+		Header("X-Request-ID", "abc").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectHeader("X-Request-ID", "abc").
+		ExpectJSON(map[string]interface{}{
+			"id":   "42",
+			"host": "example.com",
+		})
+
+	client.GET("/missing").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusNotFound)
+}
+
+func TestHandlerModeCustomBaseURL(t *testing.T) {
+	t.Parallel()
+
+	client := e2e.New(t, e2e.Config{
+		BaseURL: "http://api.internal/v1/",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `","host":"` + r.Host + `"}`))
+		}),
+	})
+
+	client.GET("users").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectJSON(`{"path":"/v1/users","host":"api.internal"}`)
+}
+
+func TestHandlerModeErrorMessage(t *testing.T) {
+	t.Parallel()
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Response: 400 Bad Request") {
+			t.Errorf("Error message should contain response status, got: %s", mt.fatalMsg)
+		}
+
+		if !strings.Contains(mt.fatalMsg, "validation failed") {
+			t.Errorf("Error message should contain response body, got: %s", mt.fatalMsg)
+		}
+	}()
+
+	client := e2e.NewWithHandler(mt, newUserHandler())
+	client.POST("/users").
+		Body(map[string]string{"name": "Alice"}).
+		Execute(context.Background()).
+		ExpectStatus(http.StatusCreated)
+}
@@ -0,0 +1,261 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+	"github.com/sivchari/e2e/test/e2e/testserver"
+)
+
+type harFile struct {
+	Log struct {
+		Version string `json:"version"`
+		Entries []struct {
+			Request struct {
+				Method      string         `json:"method"`
+				URL         string         `json:"url"`
+				Headers     []harNameValue `json:"headers"`
+				QueryString []harNameValue `json:"queryString"`
+				PostData    *struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+			Response struct {
+				Status  int            `json:"status"`
+				Headers []harNameValue `json:"headers"`
+				Content struct {
+					Size     int64  `json:"size"`
+					Text     string `json:"text"`
+					Encoding string `json:"encoding"`
+				} `json:"content"`
+			} `json:"response"`
+			Timings struct {
+				Wait float64 `json:"wait"`
+			} `json:"timings"`
+			Comment string `json:"comment"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func readHAR(t *testing.T, path string) harFile {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read HAR: %v", err)
+	}
+
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("Failed to decode HAR: %v", err)
+	}
+
+	return har
+}
+
+func headerValue(pairs []harNameValue, name string) string {
+	for _, pair := range pairs {
+		if pair.Name == name {
+			return pair.Value
+		}
+	}
+
+	return ""
+}
+
+func TestHARRecording(t *testing.T) {
+	server := testserver.NewEchoServer()
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	t.Run("record", func(t *testing.T) {
+		client := e2e.New(t, e2e.Config{BaseURL: server.URL, HARDir: dir})
+
+		client.GET("/users").
+			Query("page", "2").
+			Authorization("Bearer secret").
+			Execute(t.Context()).
+			ExpectStatus(http.StatusOK)
+
+		client.POST("/users").
+			Body(map[string]string{"name": "Alice"}).
+			Execute(t.Context()).
+			ExpectStatus(http.StatusOK)
+	})
+
+	har := readHAR(t, filepath.Join(dir, "TestHARRecording_record.har"))
+
+	if har.Log.Version != "1.2" {
+		t.Errorf("Expected HAR version 1.2, got %q", har.Log.Version)
+	}
+
+	if len(har.Log.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(har.Log.Entries))
+	}
+
+	get, post := har.Log.Entries[0], har.Log.Entries[1]
+
+	if get.Request.Method != http.MethodGet || get.Request.URL != server.URL+"/users?page=2" {
+		t.Errorf("Unexpected request: %s %s", get.Request.Method, get.Request.URL)
+	}
+
+	if got := headerValue(get.Request.QueryString, "page"); got != "2" {
+		t.Errorf("Expected query parameter page=2, got %q", got)
+	}
+
+	if got := headerValue(get.Request.Headers, "Authorization"); got != "[REDACTED]" {
+		t.Errorf("Expected Authorization to be redacted, got %q", got)
+	}
+
+	if get.Response.Status != http.StatusOK || get.Response.Content.Text == "" {
+		t.Errorf("Expected response content to be recorded, got %+v", get.Response)
+	}
+
+	if get.Timings.Wait < 0 {
+		t.Errorf("Expected a wait timing, got %v", get.Timings.Wait)
+	}
+
+	if post.Request.PostData == nil || post.Request.PostData.Text != `{"name":"Alice"}` {
+		t.Errorf("Expected post data to be recorded, got %+v", post.Request.PostData)
+	}
+
+	if post.Request.PostData != nil && post.Request.PostData.MimeType != "application/json" {
+		t.Errorf("Expected post data MIME type application/json, got %q", post.Request.PostData.MimeType)
+	}
+}
+
+func TestHARRedactHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Session", "session-token")
+		_, _ = w.Write([]byte{0xff, 0xfe, 0x00})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	t.Run("custom", func(t *testing.T) {
+		client := e2e.New(t, e2e.Config{
+			BaseURL:       server.URL,
+			HARDir:        dir,
+			RedactHeaders: []string{"x-api-key", "x-session"},
+		})
+
+		client.GET("/").
+			Header("X-Api-Key", "key").
+			Authorization("Bearer visible").
+			Execute(t.Context()).
+			ExpectStatus(http.StatusOK)
+	})
+
+	har := readHAR(t, filepath.Join(dir, "TestHARRedactHeaders_custom.har"))
+	if len(har.Log.Entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(har.Log.Entries))
+	}
+
+	entry := har.Log.Entries[0]
+
+	for _, tc := range []struct {
+		headers []harNameValue
+		name    string
+		want    string
+	}{
+		{entry.Request.Headers, "X-Api-Key", "[REDACTED]"},
+		{entry.Request.Headers, "Authorization", "Bearer visible"},
+		{entry.Response.Headers, "X-Session", "[REDACTED]"},
+	} {
+		if got := headerValue(tc.headers, tc.name); got != tc.want {
+			t.Errorf("Expected %s to be %q, got %q", tc.name, tc.want, got)
+		}
+	}
+
+	if entry.Response.Content.Encoding != "base64" || entry.Response.Content.Text != "//4A" {
+		t.Errorf("Expected binary content to be base64 encoded, got %+v", entry.Response.Content)
+	}
+}
+
+// cleanupMockT is a mockT whose cleanups run, so a failed request is still
+// written to the HAR log.
+type cleanupMockT struct {
+	*mockT
+}
+
+func (c cleanupMockT) Cleanup(f func()) { c.TB.Cleanup(f) }
+
+func TestHARRecordsEveryAttempt(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) == 1 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	refused := httptest.NewServer(http.NotFoundHandler())
+	refused.Close()
+
+	dir := t.TempDir()
+
+	t.Run("retried", func(t *testing.T) {
+		client := e2e.New(t, e2e.Config{BaseURL: server.URL, HARDir: dir})
+
+		client.GET("/").
+			Retry(e2e.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}).
+			Execute(t.Context()).
+			ExpectStatus(http.StatusOK)
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Expected the refused request to fail")
+			}
+		}()
+
+		client := e2e.New(cleanupMockT{&mockT{TB: t}}, e2e.Config{BaseURL: refused.URL, HARDir: dir})
+		client.GET("/").Execute(t.Context())
+	})
+
+	retried := readHAR(t, filepath.Join(dir, "TestHARRecordsEveryAttempt_retried.har")).Log.Entries
+	if len(retried) != 2 {
+		t.Fatalf("Expected an entry per attempt, got %d", len(retried))
+	}
+
+	if first := retried[0]; first.Response.Status != http.StatusServiceUnavailable ||
+		first.Response.Content.Text != "busy\n" || !strings.HasPrefix(first.Comment, "attempt 1, retried after") {
+		t.Errorf("Expected the retried attempt to be recorded, got %+v", first)
+	}
+
+	if last := retried[1]; last.Response.Status != http.StatusOK || last.Response.Content.Text != "ok" {
+		t.Errorf("Expected the final attempt to be recorded, got %+v", last)
+	}
+
+	failed := readHAR(t, filepath.Join(dir, "TestHARRecordsEveryAttempt_refused.har")).Log.Entries
+	if len(failed) != 1 {
+		t.Fatalf("Expected the failed request to be recorded, got %d entries", len(failed))
+	}
+
+	if entry := failed[0]; entry.Response.Status != 0 || !strings.Contains(entry.Comment, "connection refused") ||
+		entry.Request.URL != refused.URL+"/" {
+		t.Errorf("Expected the transport error in the comment, got %+v", entry)
+	}
+}
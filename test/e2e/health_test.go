@@ -0,0 +1,102 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+)
+
+func TestWaitForHealthy(t *testing.T) {
+	var probes atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusOK)
+
+			return
+		}
+
+		if probes.Add(1) <= 3 {
+			http.Error(w, "starting", http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{
+		BaseURL: server.URL,
+		WaitFor: &e2e.HealthCheck{
+			Path:     "/health",
+			Status:   http.StatusOK,
+			Body:     map[string]string{"status": "ok"},
+			Interval: 10 * time.Millisecond,
+		},
+	})
+
+	if got := probes.Load(); got != 4 {
+		t.Errorf("Expected New to probe until healthy (4 probes), got %d", got)
+	}
+
+	client.GET("/").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK)
+}
+
+func TestWaitForLargeBody(t *testing.T) {
+	status := map[string]string{"status": "ok", "details": strings.Repeat("d", 4096)}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(status)
+	}))
+	defer server.Close()
+
+	e2e.New(t, e2e.Config{
+		BaseURL: server.URL,
+		WaitFor: &e2e.HealthCheck{Body: status, Timeout: time.Second},
+	})
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "database unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+
+		for _, want := range []string{
+			"Service is not healthy: " + server.URL + "/ready was not healthy after 100ms",
+			`last probe: got 503 Service Unavailable with body "database unavailable\n"`,
+			"expected a 2xx status and body matching /ready/",
+		} {
+			if !strings.Contains(mt.fatalMsg, want) {
+				t.Errorf("Expected diagnostic to contain %q, got: %s", want, mt.fatalMsg)
+			}
+		}
+	}()
+
+	e2e.New(mt, e2e.Config{
+		BaseURL: server.URL,
+		WaitFor: &e2e.HealthCheck{
+			Path:     "/ready",
+			Body:     regexp.MustCompile(`ready`),
+			Timeout:  100 * time.Millisecond,
+			Interval: 10 * time.Millisecond,
+		},
+	})
+}
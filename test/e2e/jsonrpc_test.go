@@ -0,0 +1,166 @@
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sivchari/e2e"
+)
+
+type rpcRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params []int           `json:"params"`
+}
+
+// newRPCServer serves add and subtract. Batch replies are returned in
+// reverse order and notifications are counted in notified.
+func newRPCServer(t *testing.T, notified *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var (
+			requests []rpcRequest
+			batch    = bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+		)
+
+		if batch {
+			_ = json.Unmarshal(body, &requests)
+		} else {
+			requests = make([]rpcRequest, 1)
+			_ = json.Unmarshal(body, &requests[0])
+		}
+
+		var responses []map[string]interface{}
+
+		for _, req := range requests {
+			if req.ID == nil {
+				notified.Add(1)
+
+				continue
+			}
+
+			resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+
+			switch req.Method {
+			case "add":
+				resp["result"] = req.Params[0] + req.Params[1]
+			case "subtract":
+				resp["result"] = req.Params[0] - req.Params[1]
+			default:
+				resp["error"] = map[string]interface{}{"code": -32601, "message": "Method not found"}
+			}
+
+			responses = append(responses, resp)
+		}
+
+		slices.Reverse(responses)
+		w.Header().Set("Content-Type", "application/json")
+
+		if batch {
+			_ = json.NewEncoder(w).Encode(responses)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(responses[0])
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestRPCSingleCall(t *testing.T) {
+	var notified atomic.Int32
+
+	server := newRPCServer(t, &notified)
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	client.RPC("add", []int{1, 2}).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectRPCResult(3)
+
+	client.RPC("multiply", []int{2, 3}).
+		Execute(t.Context()).
+		ExpectRPCError(-32601)
+}
+
+func TestRPCBatchOutOfOrder(t *testing.T) {
+	var notified atomic.Int32
+
+	server := newRPCServer(t, &notified)
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	client.RPC("add", []int{1, 2}).
+		Call("subtract", []int{5, 3}).
+		Notify("log", []int{1}).
+		Call("missing", nil).
+		Execute(t.Context()).
+		ExpectRPCError(-32601).
+		At(0).ExpectRPCResult(3).
+		At(1).ExpectRPCResult(`2`)
+
+	if got := notified.Load(); got != 1 {
+		t.Errorf("Expected 1 notification, got %d", got)
+	}
+}
+
+func TestRPCCustomPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rpc" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		var req rpcRequest
+
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":{"ok":true}}`))
+	}))
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL, RPCPath: "/rpc"})
+
+	client.RPC("status", nil).
+		Execute(t.Context()).
+		ExpectRPCResult(map[string]bool{"ok": true})
+}
+
+func TestRPCErrorMessage(t *testing.T) {
+	var notified atomic.Int32
+
+	server := newRPCServer(t, &notified)
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		for _, want := range []string{
+			"=== HTTP Request Failed ===",
+			"Result mismatch (multiply, id ",
+			"Actual:   error -32601: Method not found",
+		} {
+			if !strings.Contains(mt.fatalMsg, want) {
+				t.Errorf("Expected error message to contain %q, got:\n%s", want, mt.fatalMsg)
+			}
+		}
+	}()
+
+	client.RPC("multiply", []int{2, 3}).
+		Execute(t.Context()).
+		ExpectRPCResult(6)
+}
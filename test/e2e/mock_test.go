@@ -0,0 +1,133 @@
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+	"github.com/sivchari/e2e/test/e2e/testserver"
+)
+
+// cleanupT collects cleanups and errors so mock verification can be checked.
+type cleanupT struct {
+	testing.TB
+	cleanups []func()
+	errors   []string
+}
+
+func (c *cleanupT) Cleanup(f func()) { c.cleanups = append(c.cleanups, f) }
+
+func (c *cleanupT) Errorf(format string, args ...interface{}) {
+	c.errors = append(c.errors, fmt.Sprintf(format, args...))
+}
+
+func (c *cleanupT) Helper() {}
+
+func (c *cleanupT) runCleanups() {
+	for i := len(c.cleanups) - 1; i >= 0; i-- {
+		c.cleanups[i]()
+	}
+}
+
+func TestMockServer(t *testing.T) {
+	mock := testserver.NewMock(t)
+
+	mock.Expect(http.MethodPost, "/users").
+		Header("Authorization", regexp.MustCompile(`^Bearer `)).
+		Body(map[string]string{"name": "Alice"}).
+		Respond(http.StatusCreated, map[string]interface{}{"id": 1, "name": "Alice"}).
+		RespondHeader("Location", "/users/1").
+		Times(1)
+
+	mock.Expect(http.MethodGet, "/users/{id}").
+		Query("expand", "true").
+		RespondWith(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"id":%q}`, r.PathValue("id"))
+		}).
+		Times(2)
+
+	mock.Expect("", "/files/{path...}").
+		Respond(http.StatusNoContent, nil).
+		AnyTimes()
+
+	client := e2e.New(t, e2e.Config{BaseURL: mock.URL})
+
+	client.POST("/users").
+		Authorization("Bearer token").
+		Body(`{"name": "Alice"}`).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusCreated).
+		ExpectHeader("Location", "/users/1").
+		ExpectJSON(`{"id":1,"name":"Alice"}`)
+
+	for _, id := range []string{"1", "2"} {
+		client.GET("/users/"+id).
+			Query("expand", "true").
+			Execute(t.Context()).
+			ExpectJSON(map[string]string{"id": id})
+	}
+
+	client.DELETE("/files/a/b.txt").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusNoContent)
+}
+
+func TestMockServerDelay(t *testing.T) {
+	mock := testserver.NewMock(t)
+	mock.Expect(http.MethodGet, "/slow").
+		Delay(100*time.Millisecond).
+		Respond(http.StatusOK, "done")
+
+	client := e2e.New(t, e2e.Config{BaseURL: mock.URL})
+
+	start := time.Now()
+
+	client.GET("/slow").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK)
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected delayed response, got it after %v", elapsed)
+	}
+}
+
+func TestMockServerVerification(t *testing.T) {
+	ct := &cleanupT{TB: t}
+	mock := testserver.NewMock(ct)
+
+	mock.Expect(http.MethodGet, "/health").Times(2)
+	mock.Expect(http.MethodPost, "/events").Body(`{"type":"created"}`)
+
+	client := e2e.New(t, e2e.Config{BaseURL: mock.URL})
+
+	client.GET("/health").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK)
+
+	client.POST("/events").
+		Body(map[string]string{"type": "deleted"}).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusNotImplemented)
+
+	if got := mock.Unmatched(); len(got) != 1 || got[0] != "POST /events" {
+		t.Errorf("Expected one unmatched request, got %v", got)
+	}
+
+	ct.runCleanups()
+
+	report := strings.Join(ct.errors, "\n")
+
+	for _, want := range []string{
+		"GET /health: expected 2 calls, got 1",
+		`POST /events, body {"type":"created"}: expected at least 1 call, got 0`,
+		"1 requests matched no expectation:\n  POST /events",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected verification to report %q, got:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package e2e_test
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+)
+
+func TestStartProcessWithPort(t *testing.T) {
+	process := e2e.StartProcess(t, "./testdata/server", e2e.ProcessOptions{
+		Build: true,
+		Args:  []string{"-addr", "127.0.0.1:{{port}}"},
+		Env:   []string{"GREETING=hello from {{port}}"},
+		Ready: e2e.ReadinessProbe{HTTPPath: "/health"},
+	})
+
+	_, port, _ := strings.Cut(process.Addr, ":")
+
+	process.New(t, e2e.Config{}).
+		GET("/hello").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectJSON(map[string]string{"greeting": "hello from " + port})
+}
+
+func TestStartProcessDiscoversAddress(t *testing.T) {
+	process := e2e.StartProcess(t, "./testdata/server", e2e.ProcessOptions{
+		Build: true,
+		Ready: e2e.ReadinessProbe{
+			LogLine: regexp.MustCompile(`listening on (?P<addr>\S+)`),
+			TCP:     true,
+		},
+	})
+
+	if !strings.HasPrefix(process.BaseURL, "http://127.0.0.1:") {
+		t.Errorf("Expected the logged address as BaseURL, got %q", process.BaseURL)
+	}
+
+	client := e2e.New(t, e2e.Config{BaseURL: process.BaseURL})
+	client.GET("/health").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusNoContent)
+
+	process.Stop()
+
+	if logs := process.Logs(); !strings.Contains(logs, "shutting down") {
+		t.Errorf("Expected a graceful shutdown, got logs:\n%s", logs)
+	}
+}
+
+func TestStartProcessNotReady(t *testing.T) {
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		if !strings.HasPrefix(mt.fatalMsg, "Process %s is not ready") {
+			t.Errorf("Expected a readiness failure, got %q", mt.fatalMsg)
+		}
+	}()
+
+	e2e.StartProcess(mt, "./testdata/server", e2e.ProcessOptions{
+		Build: true,
+		Args:  []string{"-fail"},
+		Ready: e2e.ReadinessProbe{
+			LogLine: regexp.MustCompile(`listening on`),
+			Timeout: 10 * time.Second,
+		},
+	})
+}
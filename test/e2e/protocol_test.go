@@ -0,0 +1,132 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sivchari/e2e"
+)
+
+// trailerHandler responds with a body followed by an X-Checksum trailer.
+func trailerHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Trailer", "X-Checksum")
+	w.Header().Set("X-Proto", r.Proto)
+	_, _ = w.Write([]byte("payload"))
+	w.Header().Set("X-Checksum", "abc123")
+}
+
+func TestHTTP2OverTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(trailerHandler))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{
+		BaseURL:  server.URL,
+		TLS:      &e2e.TLSConfig{CAPEM: serverCAPEM(server)},
+		Protocol: e2e.ProtocolHTTP2,
+	})
+
+	client.GET("/").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectProto("HTTP/2.0").
+		ExpectHeader("X-Proto", "HTTP/2.0").
+		ExpectTrailer("X-Checksum", "abc123")
+
+	// A single request can still fall back to HTTP/1.1.
+	client.GET("/").
+		Protocol(e2e.ProtocolHTTP1).
+		Execute(t.Context()).
+		ExpectProto("HTTP/1.1")
+}
+
+func TestH2C(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(trailerHandler))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	client.GET("/").
+		Execute(t.Context()).
+		ExpectProto("HTTP/1.1")
+
+	trailers := client.GET("/").
+		Protocol(e2e.ProtocolH2C).
+		Execute(t.Context()).
+		ExpectProto("HTTP/2.0").
+		Trailers()
+
+	if got := trailers.Get("X-Checksum"); got != "abc123" {
+		t.Errorf("Expected trailer abc123, got %q", got)
+	}
+}
+
+func TestHandlerModeTrailers(t *testing.T) {
+	client := e2e.NewWithHandler(t, http.HandlerFunc(trailerHandler))
+
+	client.GET("/").
+		Execute(t.Context()).
+		ExpectProto("HTTP/1.1").
+		ExpectTrailer("X-Checksum", "abc123")
+}
+
+func TestProtocolMismatchInErrorMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(trailerHandler))
+	defer server.Close()
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Protocol mismatch") {
+			t.Errorf("Error message should contain protocol mismatch, got: %s", mt.fatalMsg)
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Trailer:  X-Checksum: abc123") {
+			t.Errorf("Error message should contain trailers, got: %s", mt.fatalMsg)
+		}
+	}()
+
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+	client.GET("/").
+		Execute(context.Background()).
+		ExpectProto("HTTP/2.0")
+}
+
+func TestUnsentTrailerInErrorMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Trailer", "X-Checksum")
+		_, _ = w.Write([]byte("no trailer follows"))
+	}))
+	defer server.Close()
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r != "fatal called" {
+			t.Fatalf("Expected the failure report, got panic %v", r)
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Status code mismatch") || strings.Contains(mt.fatalMsg, "Trailer:") {
+			t.Errorf("Expected the report without the unsent trailer, got: %s", mt.fatalMsg)
+		}
+	}()
+
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+	client.GET("/").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusCreated)
+}
@@ -0,0 +1,112 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sivchari/e2e"
+)
+
+func newRedirectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.Handle("/login", http.RedirectHandler("/home", http.StatusFound))
+	mux.Handle("/a", http.RedirectHandler("/b", http.StatusMovedPermanently))
+	mux.Handle("/b", http.RedirectHandler("/c", http.StatusTemporaryRedirect))
+	mux.HandleFunc("/c", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestExpectRedirect(t *testing.T) {
+	server := newRedirectServer(t)
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	t.Run("RawLocation", func(t *testing.T) {
+		client.GET("/login").
+			FollowRedirects(false).
+			Execute(t.Context()).
+			ExpectRedirect(http.StatusFound, "/home")
+	})
+
+	t.Run("ResolvedLocation", func(t *testing.T) {
+		client.GET("/login").
+			FollowRedirects(false).
+			Execute(t.Context()).
+			ExpectRedirect(http.StatusFound, server.URL+"/home")
+	})
+}
+
+func TestRedirectChain(t *testing.T) {
+	server := newRedirectServer(t)
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+	hops := client.GET("/a").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		Redirects()
+
+	if len(hops) != 2 {
+		t.Fatalf("Expected 2 redirect hops, got %d", len(hops))
+	}
+
+	if hops[0].StatusCode != http.StatusMovedPermanently || hops[0].Location != "/b" {
+		t.Errorf("Unexpected first hop: %+v", hops[0])
+	}
+
+	if hops[1].StatusCode != http.StatusTemporaryRedirect || hops[1].Location != "/c" {
+		t.Errorf("Unexpected second hop: %+v", hops[1])
+	}
+}
+
+func TestMaxRedirects(t *testing.T) {
+	server := newRedirectServer(t)
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+	}()
+
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+	client.GET("/a").
+		MaxRedirects(1).
+		Execute(context.Background())
+}
+
+func TestRedirectChainInErrorMessage(t *testing.T) {
+	server := newRedirectServer(t)
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Redirects:") {
+			t.Errorf("Error message should contain redirect chain, got: %s", mt.fatalMsg)
+		}
+
+		if !strings.Contains(mt.fatalMsg, "301 /b") || !strings.Contains(mt.fatalMsg, "307 /c") {
+			t.Errorf("Error message should contain every hop, got: %s", mt.fatalMsg)
+		}
+	}()
+
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+	client.GET("/a").
+		Execute(context.Background()).
+		ExpectStatus(http.StatusNoContent)
+}
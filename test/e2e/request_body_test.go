@@ -1,7 +1,18 @@
 package e2e_test
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/sivchari/e2e"
 	"github.com/sivchari/e2e/test/e2e/testserver"
@@ -83,3 +94,173 @@ func TestRequestBodyJSONString(t *testing.T) {
 			},
 		})
 }
+
+// newUploadServer reports how the request body arrived.
+func newUploadServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := io.Copy(io.Discard, r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"content_length":    r.ContentLength,
+			"transfer_encoding": strings.Join(r.TransferEncoding, ","),
+			"content_type":      r.Header.Get("Content-Type"),
+			"size":              n,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestStreamBody(t *testing.T) {
+	server := newUploadServer(t)
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	client.POST("/upload").
+		Body(strings.NewReader(strings.Repeat("a", 1<<20))).
+		Execute(t.Context()).
+		ExpectJSON(`{"content_length":-1,"transfer_encoding":"chunked","content_type":"application/octet-stream","size":1048576}`)
+
+	client.POST("/upload").
+		Body(strings.NewReader("hello")).
+		ContentLength(5).
+		Execute(t.Context()).
+		ExpectJSON(`{"content_length":5,"transfer_encoding":"","content_type":"application/octet-stream","size":5}`)
+}
+
+func TestBytesBody(t *testing.T) {
+	server := newUploadServer(t)
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	client.PUT("/upload").
+		Body([]byte{0x00, 0x01, 0x02}).
+		Execute(t.Context()).
+		ExpectJSON(`{"content_length":3,"transfer_encoding":"","content_type":"application/octet-stream","size":3}`)
+
+	client.PUT("/upload").
+		Body([]byte("chunked")).
+		Chunked().
+		Execute(t.Context()).
+		ExpectJSON(`{"content_length":-1,"transfer_encoding":"chunked","content_type":"application/octet-stream","size":7}`)
+}
+
+func TestGeneratorBodyIsRetried(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	var generated atomic.Int32
+
+	want := sha256.Sum256([]byte("line 0\nline 1\nline 2\n"))
+
+	client.POST("/").
+		Body(func(w io.Writer) error {
+			generated.Add(1)
+
+			for i := range 3 {
+				if _, err := fmt.Fprintf(w, "line %d\n", i); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}).
+		Retry(e2e.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectSHA256(hex.EncodeToString(want[:]))
+
+	if got := generated.Load(); got != 2 {
+		t.Errorf("Expected the generator to run once per attempt, ran %d times", got)
+	}
+}
+
+func TestReaderBodyIsNotRetried(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	client.POST("/").
+		Body(bytes.NewBufferString("once")).
+		Retry(e2e.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusServiceUnavailable)
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected a single request for a stream body, got %d", got)
+	}
+}
+
+func TestThrottledBody(t *testing.T) {
+	server := newUploadServer(t)
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	start := time.Now()
+
+	client.POST("/upload").
+		Body([]byte(strings.Repeat("x", 2000))).
+		Throttle(10000).
+		Execute(t.Context()).
+		ExpectJSON(`{"content_length":2000,"transfer_encoding":"","content_type":"application/octet-stream","size":2000}`)
+
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("Expected throttled upload to take about 200ms, took %v", elapsed)
+	}
+}
+
+func TestStreamBodyErrorMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	}))
+	defer server.Close()
+
+	mt := &mockT{TB: t}
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected Fatal to be called")
+		}
+
+		for _, want := range []string{
+			"Body:     " + strings.Repeat("z", 100),
+			"Sent:     5000 bytes streamed",
+		} {
+			if !strings.Contains(mt.fatalMsg, want) {
+				t.Errorf("Expected error message to contain %q, got:\n%s", want, mt.fatalMsg)
+			}
+		}
+
+		if strings.Contains(mt.fatalMsg, strings.Repeat("z", 2000)) {
+			t.Error("Expected sent body to be capped in the error message")
+		}
+	}()
+
+	client.POST("/").
+		Body(strings.NewReader(strings.Repeat("z", 5000))).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusCreated)
+}
@@ -0,0 +1,178 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+)
+
+// newFlakyServer returns a server that responds with status for the first failures requests.
+func newFlakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) <= failures {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestRetryOnStatus(t *testing.T) {
+	server, calls := newFlakyServer(t, 2, http.StatusServiceUnavailable)
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+	client.GET("/warmup").
+		Retry(e2e.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK)
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestRetrySuiteDefault(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusBadGateway)
+
+	client := e2e.New(t, e2e.Config{
+		BaseURL: server.URL,
+		Retry:   &e2e.RetryPolicy{InitialBackoff: time.Millisecond},
+	})
+	client.POST("/jobs").
+		Body(map[string]string{"name": "build"}).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK)
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+}
+
+func TestRetryIgnoresOtherStatus(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusInternalServerError)
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+	client.GET("/").
+		Retry(e2e.RetryPolicy{InitialBackoff: time.Millisecond}).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusInternalServerError)
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestRetryOnConnectionError(t *testing.T) {
+	server, _ := newFlakyServer(t, 0, http.StatusOK)
+
+	var calls atomic.Int32
+
+	client := e2e.New(t, e2e.Config{
+		BaseURL: server.URL,
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if calls.Add(1) == 1 {
+				return nil, context.DeadlineExceeded
+			}
+
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+		Retry: &e2e.RetryPolicy{
+			InitialBackoff: time.Millisecond,
+			RetryOnError:   func(error) bool { return true },
+		},
+	})
+
+	client.GET("/").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK)
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+}
+
+func TestRetryAttemptHistoryInErrorMessage(t *testing.T) {
+	server, _ := newFlakyServer(t, 5, http.StatusServiceUnavailable)
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Attempts: 3") {
+			t.Errorf("Error message should contain attempt count, got: %s", mt.fatalMsg)
+		}
+
+		if !strings.Contains(mt.fatalMsg, "#1 503 Service Unavailable") {
+			t.Errorf("Error message should contain first attempt, got: %s", mt.fatalMsg)
+		}
+	}()
+
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+	client.GET("/").
+		Retry(e2e.RetryPolicy{MaxAttempts: 3}).
+		Execute(context.Background()).
+		ExpectStatus(http.StatusOK)
+}
+
+func TestIsTransientError(t *testing.T) {
+	if e2e.IsTransientError(context.Canceled) {
+		t.Error("Context cancellation should not be transient")
+	}
+
+	_, err := http.Get("http://127.0.0.1:1") //nolint:noctx // Only the dial error matters here.
+	if !e2e.IsTransientError(err) {
+		t.Errorf("Connection refused should be transient, got: %v", err)
+	}
+}
+
+func TestRetryAfterBeyondDeadline(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL, Timeout: 300 * time.Millisecond})
+
+	client.GET("/").
+		Retry(e2e.RetryPolicy{MaxAttempts: 3, MaxBackoff: time.Minute}).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusServiceUnavailable)
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected the last response to be kept instead of waiting, got %d attempts", got)
+	}
+
+	calls.Store(0)
+
+	client.GET("/").
+		Retry(e2e.RetryPolicy{MaxAttempts: 3, MaxBackoff: 10 * time.Millisecond}).
+		Execute(t.Context()).
+		ExpectStatus(http.StatusServiceUnavailable)
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Expected Retry-After to be capped at MaxBackoff, got %d attempts", got)
+	}
+}
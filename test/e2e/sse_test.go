@@ -0,0 +1,165 @@
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+)
+
+// newEventServer streams numbered "tick" events, resuming after Last-Event-ID,
+// and keeps the connection open after the last one.
+func newEventServer(t *testing.T, total int) *httptest.Server {
+	t.Helper()
+
+	done := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+
+		start, _ := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+		flusher, _ := w.(http.Flusher)
+
+		_, _ = fmt.Fprint(w, ": connected\r\nretry: 10\r\n\r\n")
+
+		for i := start + 1; i <= total; i++ {
+			_, _ = fmt.Fprintf(w, "event: tick\nid: %d\ndata: {\"n\":%d}\n\n", i, i)
+			flusher.Flush()
+		}
+
+		_, _ = fmt.Fprint(w, "data: line one\ndata: line two\n\n")
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(done) })
+
+	return server
+}
+
+func TestExpectSSE(t *testing.T) {
+	server := newEventServer(t, 3)
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+	stream := client.GET("/events").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectSSE().
+		Event("tick", `{"n":1}`).ID("1").
+		Event("tick", map[string]int{"n": 3}).ID("3").
+		Event("message", "line one\nline two").
+		Within(5 * time.Second)
+
+	if events := stream.Events(); len(events) != 4 {
+		t.Errorf("Expected 4 events, got %d: %v", len(events), events)
+	}
+}
+
+func TestSSEReconnect(t *testing.T) {
+	server := newEventServer(t, 3)
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+	client.GET("/events").
+		Execute(t.Context()).
+		ExpectSSE().
+		Event("tick", nil).ID("2").
+		Within(5*time.Second).
+		Reconnect(t.Context()).
+		Event("message", nil).
+		Within(5 * time.Second)
+
+	// The server resumes after the last event ID the client received.
+	events := client.GET("/events").
+		Header("Last-Event-ID", "2").
+		Execute(t.Context()).
+		ExpectSSE().
+		Event("message", nil).
+		Within(5 * time.Second).
+		Events()
+
+	if len(events) != 2 || events[0].ID != "3" {
+		t.Errorf("Expected to resume at event 3, got %v", events)
+	}
+}
+
+func TestSSEErrorMessage(t *testing.T) {
+	server := newEventServer(t, 2)
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "SSE expectation not met (timed out after 100ms)") {
+			t.Errorf("Error message should contain the failed expectation, got: %s", mt.fatalMsg)
+		}
+
+		if !strings.Contains(mt.fatalMsg, `#2 event=tick data={"n":2} id=2`) {
+			t.Errorf("Error message should contain received events, got: %s", mt.fatalMsg)
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Expected: event=done") {
+			t.Errorf("Error message should contain the expected event, got: %s", mt.fatalMsg)
+		}
+	}()
+
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+	client.GET("/events").
+		Execute(context.Background()).
+		ExpectSSE().
+		Event("tick", nil).
+		Event("done", nil).
+		Within(100 * time.Millisecond)
+}
+
+func TestSSERequestTimeoutInErrorMessage(t *testing.T) {
+	server := newEventServer(t, 1)
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "request timeout of 100ms, raise it with Timeout") {
+			t.Errorf("Error message should point at the request timeout, got: %s", mt.fatalMsg)
+		}
+	}()
+
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL, Timeout: 100 * time.Millisecond})
+	client.GET("/events").
+		Execute(t.Context()).
+		ExpectSSE().
+		Event("done", nil).
+		Within(5 * time.Second)
+}
+
+func TestSSEHandlerMode(t *testing.T) {
+	client := e2e.NewWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		_, _ = fmt.Fprint(w, "event: ready\ndata: ok\n\n")
+		w.(http.Flusher).Flush()
+
+		<-r.Context().Done()
+	}))
+
+	client.GET("/events").
+		Execute(t.Context()).
+		ExpectSSE().
+		Event("ready", "ok").
+		Within(5 * time.Second)
+}
@@ -0,0 +1,305 @@
+package testserver
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+)
+
+// faultKind identifies what a Fault does.
+type faultKind int
+
+const (
+	faultLatency faultKind = iota
+	faultDrop
+	faultTruncate
+	faultStatus
+	faultBandwidth
+)
+
+// Fault is a failure a ChaosProxy injects into matching requests.
+type Fault struct {
+	kind   faultKind
+	delay  time.Duration
+	status int
+	limit  int64
+}
+
+// Latency delays the request by d before it is forwarded.
+func Latency(d time.Duration) Fault {
+	return Fault{kind: faultLatency, delay: d}
+}
+
+// Drop closes the connection without sending a response.
+func Drop() Fault {
+	return Fault{kind: faultDrop}
+}
+
+// Truncate forwards the request but cuts the response body after n bytes
+// and closes the connection, so the client sees an unexpected EOF.
+func Truncate(n int64) Fault {
+	return Fault{kind: faultTruncate, limit: n}
+}
+
+// Status answers with status code instead of forwarding the request.
+func Status(code int) Fault {
+	return Fault{kind: faultStatus, status: code}
+}
+
+// Bandwidth delivers the response body at most bytesPerSecond.
+func Bandwidth(bytesPerSecond int64) Fault {
+	return Fault{kind: faultBandwidth, limit: bytesPerSecond}
+}
+
+// String describes the fault.
+func (f Fault) String() string {
+	switch f.kind {
+	case faultLatency:
+		return fmt.Sprintf("latency %s", f.delay)
+	case faultDrop:
+		return "drop"
+	case faultTruncate:
+		return fmt.Sprintf("truncate after %d bytes", f.limit)
+	case faultStatus:
+		return fmt.Sprintf("status %d", f.status)
+	case faultBandwidth:
+		return fmt.Sprintf("bandwidth %d B/s", f.limit)
+	default:
+		return "unknown fault"
+	}
+}
+
+// ChaosProxy is a reverse proxy that injects faults between the client and
+// the service under test. Without rules it forwards every request unchanged.
+type ChaosProxy struct {
+	*httptest.Server
+
+	proxy *httputil.ReverseProxy
+
+	mu    sync.Mutex
+	rules []*FaultRule
+	rand  *rand.Rand
+}
+
+// FaultRule applies a fault to matching requests.
+type FaultRule struct {
+	mu          *sync.Mutex
+	fault       Fault
+	method      string
+	pattern     string
+	probability float64
+	times       int
+	hits        int
+}
+
+// NewChaosProxy starts a proxy in front of target, such as the URL of the
+// service under test. Point the suite's BaseURL at the proxy's URL.
+func NewChaosProxy(target string) *ChaosProxy {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		panic(fmt.Sprintf("testserver: invalid chaos proxy target %q: %v", target, err))
+	}
+
+	p := &ChaosProxy{
+		proxy: httputil.NewSingleHostReverseProxy(targetURL),
+		rand:  rand.New(rand.NewPCG(1, 2)), //nolint:gosec // Fault decisions need to be reproducible, not secure.
+	}
+	p.Server = httptest.NewServer(p)
+
+	return p
+}
+
+// Inject adds a rule that applies fault to every request; narrow it with
+// Method, Path, Probability and Times. Every matching rule applies, in the
+// order the rules were added, and Drop and Status end the request.
+func (p *ChaosProxy) Inject(fault Fault) *FaultRule {
+	rule := &FaultRule{mu: &p.mu, fault: fault, probability: 1}
+
+	p.mu.Lock()
+	p.rules = append(p.rules, rule)
+	p.mu.Unlock()
+
+	return rule
+}
+
+// Reset removes every rule, so requests are forwarded unchanged.
+func (p *ChaosProxy) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rules = nil
+}
+
+// Seed reseeds the generator behind Probability, making a run repeatable.
+func (p *ChaosProxy) Seed(seed uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rand = rand.New(rand.NewPCG(seed, seed)) //nolint:gosec // Fault decisions need to be reproducible, not secure.
+}
+
+// Method limits the rule to requests with method.
+func (r *FaultRule) Method(method string) *FaultRule {
+	r.method = method
+
+	return r
+}
+
+// Path limits the rule to request paths matching pattern, using the
+// syntax of path.Match, such as "/users/*".
+func (r *FaultRule) Path(pattern string) *FaultRule {
+	r.pattern = pattern
+
+	return r
+}
+
+// Probability applies the fault to a matching request with probability p,
+// between 0 and 1.
+func (r *FaultRule) Probability(p float64) *FaultRule {
+	r.probability = p
+
+	return r
+}
+
+// Times stops applying the fault after n requests.
+func (r *FaultRule) Times(n int) *FaultRule {
+	r.times = n
+
+	return r
+}
+
+// Hits returns how many requests the fault was applied to.
+func (r *FaultRule) Hits() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.hits
+}
+
+// faultsFor picks the faults to apply to req.
+func (p *ChaosProxy) faultsFor(req *http.Request) []Fault {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var faults []Fault
+
+	for _, rule := range p.rules {
+		if !rule.matches(req) || p.rand.Float64() >= rule.probability {
+			continue
+		}
+
+		rule.hits++
+		faults = append(faults, rule.fault)
+	}
+
+	return faults
+}
+
+// matches reports whether the rule applies to req.
+func (r *FaultRule) matches(req *http.Request) bool {
+	if r.times > 0 && r.hits >= r.times {
+		return false
+	}
+
+	if r.method != "" && r.method != req.Method {
+		return false
+	}
+
+	if r.pattern == "" {
+		return true
+	}
+
+	matched, err := path.Match(r.pattern, req.URL.Path)
+
+	return err == nil && matched
+}
+
+// ServeHTTP applies the matching faults and forwards the request.
+func (p *ChaosProxy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	out := &faultWriter{ResponseWriter: w, limit: -1}
+
+	for _, fault := range p.faultsFor(req) {
+		switch fault.kind {
+		case faultLatency:
+			select {
+			case <-time.After(fault.delay):
+			case <-req.Context().Done():
+				return
+			}
+		case faultDrop:
+			panic(http.ErrAbortHandler)
+		case faultStatus:
+			http.Error(w, "chaos: injected "+fault.String(), fault.status)
+
+			return
+		case faultTruncate:
+			out.limit = fault.limit
+		case faultBandwidth:
+			out.rate = fault.limit
+		}
+	}
+
+	p.proxy.ServeHTTP(out, req)
+
+	if out.cut {
+		// Aborting the handler closes the connection mid-body.
+		_ = http.NewResponseController(w).Flush()
+
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// faultWriter truncates and throttles a response body.
+type faultWriter struct {
+	http.ResponseWriter
+
+	limit   int64 // bytes to deliver; -1 for all
+	rate    int64 // bytes per second; 0 for unlimited
+	written int64
+	cut     bool
+}
+
+func (w *faultWriter) Write(p []byte) (int, error) {
+	n := len(p)
+
+	if w.limit >= 0 && w.written+int64(len(p)) > w.limit {
+		p = p[:w.limit-w.written]
+		w.cut = true
+	}
+
+	for len(p) > 0 {
+		chunk := p
+		if w.rate > 0 {
+			chunk = p[:min(int64(len(p)), max(w.rate/10, 1))]
+		}
+
+		written, err := w.ResponseWriter.Write(chunk)
+		w.written += int64(written)
+
+		if err != nil {
+			return written, fmt.Errorf("chaos: failed to write response: %w", err)
+		}
+
+		p = p[written:]
+
+		if w.rate > 0 {
+			_ = http.NewResponseController(w.ResponseWriter).Flush()
+
+			time.Sleep(time.Duration(float64(written) / float64(w.rate) * float64(time.Second)))
+		}
+	}
+
+	// Report the full write so the proxy keeps copying after a cut.
+	return n, nil
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *faultWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -2,9 +2,21 @@
 package testserver
 
 import (
+	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 // NewEchoServer creates a test server that echoes request details.
@@ -12,17 +24,137 @@ func NewEchoServer() *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(echoHandler))
 }
 
+// NewTLSEchoServer creates an echo server that serves HTTPS. Use its
+// Client, or trust its Certificate, to connect.
+func NewTLSEchoServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(echoHandler))
+}
+
+// NewUnixEchoServer creates an echo server listening on the unix socket at
+// socketPath. Clients dial the socket directly; the server's URL is not
+// routable over TCP.
+func NewUnixEchoServer(socketPath string) *httptest.Server {
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		panic(fmt.Sprintf("testserver: failed to listen on %s: %v", socketPath, err))
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(echoHandler))
+	_ = server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+
+	return server
+}
+
 // echoHandler echoes back request information for testing.
+// WebSocket handshakes are upgraded to a message echo.
+//
+// The JSON response holds the method, path, query and JSON body. Bodies
+// that are not JSON are returned base64 encoded as rawBody, as are all
+// bodies when the raw query parameter is true. Request headers are echoed
+// with the X-Echo- prefix and request metadata with X-Echo-Meta-. The
+// status, delay and header query parameters shape the response; see
+// parseKnobs.
 func echoHandler(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		wsEchoHandler(w, r)
+
+		return
+	}
+
+	knobs, err := parseKnobs(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read body: %v", err), http.StatusBadRequest)
+
+		return
+	}
+
+	if knobs.delay > 0 {
+		select {
+		case <-time.After(knobs.delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
 	// Echo headers back with X-Echo- prefix
-	echoHeaders(w, r.Header)
+	echoHeaders(w.Header(), r.Header)
+	echoMeta(w.Header(), r, len(body))
+
+	for key, values := range knobs.headers {
+		w.Header()[key] = values
+	}
+
+	response := buildEchoResponse(r, body, knobs.raw)
+	sendJSONResponse(w, knobs.status, response)
+}
+
+// echoKnobs are query parameters that shape the echo response.
+type echoKnobs struct {
+	status  int
+	delay   time.Duration
+	headers http.Header
+	raw     bool
+}
+
+// parseKnobs reads the response knobs from query:
+//
+//   - status=201 sets the response status code
+//   - delay=100ms waits before responding
+//   - header=Name:Value adds a response header; it may be repeated
+//   - raw=true includes rawBody even for JSON bodies
+func parseKnobs(query url.Values) (echoKnobs, error) {
+	knobs := echoKnobs{status: http.StatusOK, headers: make(http.Header)}
+
+	if value := query.Get("status"); value != "" {
+		status, err := strconv.Atoi(value)
+		if err != nil || status < 100 || status > 999 {
+			return knobs, fmt.Errorf("invalid status %q", value)
+		}
 
-	response := buildEchoResponse(r)
-	sendJSONResponse(w, response)
+		knobs.status = status
+	}
+
+	if value := query.Get("delay"); value != "" {
+		delay, err := time.ParseDuration(value)
+		if err != nil {
+			return knobs, fmt.Errorf("invalid delay %q: %w", value, err)
+		}
+
+		knobs.delay = delay
+	}
+
+	for _, header := range query["header"] {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return knobs, fmt.Errorf("invalid header %q, want Name:Value", header)
+		}
+
+		knobs.headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	if value := query.Get("raw"); value != "" {
+		raw, err := strconv.ParseBool(value)
+		if err != nil {
+			return knobs, fmt.Errorf("invalid raw %q: %w", value, err)
+		}
+
+		knobs.raw = raw
+	}
+
+	return knobs, nil
 }
 
 // buildEchoResponse constructs the echo response from the request.
-func buildEchoResponse(r *http.Request) map[string]interface{} {
+func buildEchoResponse(r *http.Request, body []byte, raw bool) map[string]interface{} {
 	response := map[string]interface{}{
 		"method": r.Method,
 		"path":   r.URL.Path,
@@ -34,10 +166,17 @@ func buildEchoResponse(r *http.Request) map[string]interface{} {
 	}
 
 	// Include body for non-GET/HEAD requests
-	if shouldIncludeBody(r.Method) {
-		if body := parseRequestBody(r); body != nil {
-			response["body"] = body
-		}
+	if !shouldIncludeBody(r.Method) || len(body) == 0 {
+		return response
+	}
+
+	parsed := parseRequestBody(body)
+	if parsed != nil {
+		response["body"] = parsed
+	}
+
+	if parsed == nil || raw {
+		response["rawBody"] = base64.StdEncoding.EncodeToString(body)
 	}
 
 	return response
@@ -51,31 +190,45 @@ func shouldIncludeBody(method string) bool {
 }
 
 // parseRequestBody attempts to parse the request body as JSON.
-func parseRequestBody(r *http.Request) interface{} {
-	if r.Body == nil {
-		return nil
-	}
-
+func parseRequestBody(data []byte) interface{} {
 	var body interface{}
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+	if err := json.Unmarshal(data, &body); err != nil {
 		return nil
 	}
 
 	return body
 }
 
-// echoHeaders copies request headers to response with X-Echo- prefix.
-func echoHeaders(w http.ResponseWriter, headers http.Header) {
+// echoHeaders copies every request header value to dst with the X-Echo-
+// prefix.
+func echoHeaders(dst, headers http.Header) {
 	for key, values := range headers {
-		if len(values) > 0 {
-			w.Header().Set("X-Echo-"+key, values[0])
-		}
+		dst["X-Echo-"+key] = slices.Clone(values)
+	}
+}
+
+// echoMeta reports how the request arrived in X-Echo-Meta- headers.
+func echoMeta(dst http.Header, r *http.Request, bodyLength int) {
+	dst.Set("X-Echo-Meta-Host", r.Host)
+	dst.Set("X-Echo-Meta-Proto", r.Proto)
+	dst.Set("X-Echo-Meta-Remote-Addr", r.RemoteAddr)
+	dst.Set("X-Echo-Meta-Content-Length", strconv.FormatInt(r.ContentLength, 10))
+	dst.Set("X-Echo-Meta-Body-Length", strconv.Itoa(bodyLength))
+
+	if len(r.TransferEncoding) > 0 {
+		dst.Set("X-Echo-Meta-Transfer-Encoding", strings.Join(r.TransferEncoding, ", "))
+	}
+
+	if r.TLS != nil {
+		dst.Set("X-Echo-Meta-TLS", tls.VersionName(r.TLS.Version))
+	} else {
+		dst.Set("X-Echo-Meta-TLS", "none")
 	}
 }
 
 // sendJSONResponse sends the response as JSON.
-func sendJSONResponse(w http.ResponseWriter, data interface{}) {
+func sendJSONResponse(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(data)
 }
@@ -0,0 +1,373 @@
+package testserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+)
+
+// Mock is a stub server that answers requests from registered expectations.
+// When the test ends it verifies that every expectation was met and reports
+// requests that matched none of them.
+type Mock struct {
+	*httptest.Server
+
+	tb           testing.TB
+	mu           sync.Mutex
+	expectations []*Expectation
+	unmatched    []string
+}
+
+// Expectation describes requests the mock should receive and how it
+// replies to them. Matchers follow the rules of e2e.MatchText.
+type Expectation struct {
+	method  string
+	pattern string
+	query   map[string]interface{}
+	headers map[string]interface{}
+	body    interface{}
+	hasBody bool
+
+	status  int
+	reply   []byte
+	header  http.Header
+	handler http.HandlerFunc
+	delay   time.Duration
+
+	// times is the exact number of calls expected; 0 means at least one,
+	// and -1 means any number.
+	times int
+	calls int
+}
+
+// NewMock starts a mock server that is closed and verified when tb ends.
+func NewMock(tb testing.TB) *Mock {
+	tb.Helper()
+
+	m := &Mock{tb: tb}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serve))
+
+	tb.Cleanup(func() {
+		m.Close()
+		m.verify()
+	})
+
+	return m
+}
+
+// Expect registers an expectation for method and path pattern. The pattern
+// uses the ServeMux wildcard syntax: {name} matches one segment and
+// {name...} the rest of the path; matched values are available through
+// r.PathValue in RespondWith handlers. An empty method matches any method.
+// Requests are matched against expectations in registration order.
+func (m *Mock) Expect(method, pattern string) *Expectation {
+	e := &Expectation{
+		method:  method,
+		pattern: pattern,
+		query:   make(map[string]interface{}),
+		headers: make(map[string]interface{}),
+		status:  http.StatusOK,
+		header:  make(http.Header),
+	}
+
+	m.mu.Lock()
+	m.expectations = append(m.expectations, e)
+	m.mu.Unlock()
+
+	return e
+}
+
+// Unmatched returns the requests that matched no expectation so far.
+func (m *Mock) Unmatched() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.unmatched)
+}
+
+// Query requires the query parameter key to satisfy matcher.
+func (e *Expectation) Query(key string, matcher interface{}) *Expectation {
+	e.query[key] = matcher
+
+	return e
+}
+
+// Header requires the request header key to satisfy matcher.
+func (e *Expectation) Header(key string, matcher interface{}) *Expectation {
+	e.headers[key] = matcher
+
+	return e
+}
+
+// Body requires the request body to satisfy matcher.
+func (e *Expectation) Body(matcher interface{}) *Expectation {
+	e.body = matcher
+	e.hasBody = true
+
+	return e
+}
+
+// Respond sets the canned response. Strings and byte slices are sent as is;
+// other values are encoded as JSON with an application/json Content-Type.
+func (e *Expectation) Respond(status int, body interface{}) *Expectation {
+	e.status = status
+
+	switch b := body.(type) {
+	case nil:
+		e.reply = nil
+	case string:
+		e.reply = []byte(b)
+	case []byte:
+		e.reply = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			panic(fmt.Sprintf("testserver: failed to encode response body: %v", err))
+		}
+
+		e.reply = data
+		e.header.Set("Content-Type", "application/json")
+	}
+
+	return e
+}
+
+// RespondHeader adds a header to the canned response.
+func (e *Expectation) RespondHeader(key, value string) *Expectation {
+	e.header.Add(key, value)
+
+	return e
+}
+
+// RespondWith replies with handler instead of the canned response.
+func (e *Expectation) RespondWith(handler http.HandlerFunc) *Expectation {
+	e.handler = handler
+
+	return e
+}
+
+// Delay waits before replying, or until the client gives up.
+func (e *Expectation) Delay(d time.Duration) *Expectation {
+	e.delay = d
+
+	return e
+}
+
+// Times expects exactly n calls. Once they are used up, further requests
+// fall through to later expectations.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+
+	return e
+}
+
+// AnyTimes allows any number of calls, including none.
+func (e *Expectation) AnyTimes() *Expectation {
+	e.times = -1
+
+	return e
+}
+
+// serve answers r with the first matching expectation.
+func (m *Mock) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	e := m.match(r, body)
+	if e == nil {
+		http.Error(w, fmt.Sprintf("testserver: no expectation matches %s", describeRequest(r)), http.StatusNotImplemented)
+
+		return
+	}
+
+	if e.delay > 0 {
+		select {
+		case <-time.After(e.delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if e.handler != nil {
+		e.handler(w, r)
+
+		return
+	}
+
+	for key, values := range e.header {
+		w.Header()[key] = values
+	}
+
+	w.WriteHeader(e.status)
+	_, _ = w.Write(e.reply)
+}
+
+// match finds and counts the expectation for r, recording r as unmatched
+// when there is none.
+func (m *Mock) match(r *http.Request, body []byte) *Expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range m.expectations {
+		if e.times > 0 && e.calls >= e.times {
+			continue
+		}
+
+		values, ok := e.matches(r, body)
+		if !ok {
+			continue
+		}
+
+		for name, value := range values {
+			r.SetPathValue(name, value)
+		}
+
+		e.calls++
+
+		return e
+	}
+
+	m.unmatched = append(m.unmatched, describeRequest(r))
+
+	return nil
+}
+
+// matches reports whether r satisfies e, returning the path wildcard values.
+func (e *Expectation) matches(r *http.Request, body []byte) (map[string]string, bool) {
+	if e.method != "" && e.method != r.Method {
+		return nil, false
+	}
+
+	values, ok := matchPattern(e.pattern, r.URL.Path)
+	if !ok {
+		return nil, false
+	}
+
+	query := r.URL.Query()
+	for key, matcher := range e.query {
+		if !query.Has(key) || !e2e.MatchText(query.Get(key), matcher) {
+			return nil, false
+		}
+	}
+
+	for key, matcher := range e.headers {
+		if r.Header.Get(key) == "" || !e2e.MatchText(r.Header.Get(key), matcher) {
+			return nil, false
+		}
+	}
+
+	if e.hasBody && !e2e.MatchText(string(body), e.body) {
+		return nil, false
+	}
+
+	return values, true
+}
+
+// matchPattern matches path against a ServeMux style pattern.
+func matchPattern(pattern, path string) (map[string]string, bool) {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	values := make(map[string]string)
+
+	for i, segment := range patternSegments {
+		name, wildcard := strings.CutPrefix(segment, "{")
+		name, _ = strings.CutSuffix(name, "}")
+
+		if rest, ok := strings.CutSuffix(name, "..."); wildcard && ok {
+			values[rest] = strings.Join(pathSegments[min(i, len(pathSegments)):], "/")
+
+			return values, true
+		}
+
+		if i >= len(pathSegments) {
+			return nil, false
+		}
+
+		switch {
+		case wildcard && pathSegments[i] != "":
+			values[name] = pathSegments[i]
+		case segment != pathSegments[i]:
+			return nil, false
+		}
+	}
+
+	return values, len(patternSegments) == len(pathSegments)
+}
+
+// verify reports unmet expectations and unmatched requests.
+func (m *Mock) verify() {
+	m.tb.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var unmet []string
+
+	for _, e := range m.expectations {
+		switch {
+		case e.times < 0:
+		case e.times == 0 && e.calls == 0:
+			unmet = append(unmet, fmt.Sprintf("  %s: expected at least 1 call, got 0", e))
+		case e.times > 0 && e.calls != e.times:
+			unmet = append(unmet, fmt.Sprintf("  %s: expected %d calls, got %d", e, e.times, e.calls))
+		}
+	}
+
+	if len(unmet) > 0 {
+		m.tb.Errorf("testserver: %d expectations not met:\n%s", len(unmet), strings.Join(unmet, "\n"))
+	}
+
+	if len(m.unmatched) > 0 {
+		m.tb.Errorf("testserver: %d requests matched no expectation:\n  %s", len(m.unmatched), strings.Join(m.unmatched, "\n  "))
+	}
+}
+
+// String describes the expectation for verification failures.
+func (e *Expectation) String() string {
+	method := e.method
+	if method == "" {
+		method = "*"
+	}
+
+	parts := []string{method + " " + e.pattern}
+
+	for _, key := range sortedKeys(e.query) {
+		parts = append(parts, fmt.Sprintf("query %s=%s", key, e2e.DescribeMatcher(e.query[key])))
+	}
+
+	for _, key := range sortedKeys(e.headers) {
+		parts = append(parts, fmt.Sprintf("header %s: %s", key, e2e.DescribeMatcher(e.headers[key])))
+	}
+
+	if e.hasBody {
+		parts = append(parts, "body "+e2e.DescribeMatcher(e.body))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// describeRequest formats r as its method and request URI.
+func describeRequest(r *http.Request) string {
+	return r.Method + " " + r.URL.RequestURI()
+}
@@ -0,0 +1,37 @@
+package testserver
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// upgrader accepts WebSocket handshakes from any origin.
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// wsEchoHandler upgrades the connection and echoes every message back with
+// the same message type until the client closes it. Handshake headers are
+// echoed with the X-Echo- prefix like regular requests.
+func wsEchoHandler(w http.ResponseWriter, r *http.Request) {
+	responseHeader := make(http.Header)
+	echoHeaders(responseHeader, r.Header)
+
+	conn, err := upgrader.Upgrade(w, r, responseHeader)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if err := conn.WriteMessage(messageType, data); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package e2e_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sivchari/e2e"
+)
+
+// serverCAPEM returns the PEM encoded certificate of a TLS test server.
+func serverCAPEM(server *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+// newClientCertificate generates a self-signed client certificate and its PEM encoded key pair.
+func newClientCertificate(t *testing.T, commonName string) (*x509.Certificate, []byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return cert, certPEM, keyPEM
+}
+
+func TestTLSCustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, serverCAPEM(server), 0o600); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+
+	client := e2e.New(t, e2e.Config{
+		BaseURL: server.URL,
+		TLS: &e2e.TLSConfig{
+			CAFile:     caFile,
+			ServerName: "example.com",
+			MinVersion: tls.VersionTLS12,
+		},
+	})
+
+	client.GET("/").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectTLS(e2e.TLSExpectation{
+			Version: tls.VersionTLS13,
+			Subject: "O=Acme Co",
+			SANs:    []string{"example.com", "127.0.0.1"},
+		})
+}
+
+func TestMutualTLS(t *testing.T) {
+	clientCert, certPEM, keyPEM := newClientCertificate(t, "orders-service")
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Client-CN", r.TLS.PeerCertificates[0].Subject.CommonName)
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	server.StartTLS()
+
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{
+		BaseURL: server.URL,
+		TLS: &e2e.TLSConfig{
+			CAPEM:   serverCAPEM(server),
+			CertPEM: certPEM,
+			KeyPEM:  keyPEM,
+		},
+	})
+
+	client.GET("/").
+		Execute(t.Context()).
+		ExpectStatus(http.StatusOK).
+		ExpectHeader("X-Client-CN", "orders-service")
+}
+
+func TestExpectTLSMismatch(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "TLS mismatch (SAN)") {
+			t.Errorf("Error message should contain TLS mismatch, got: %s", mt.fatalMsg)
+		}
+
+		if !strings.Contains(mt.fatalMsg, "TLS:      TLS 1.3") {
+			t.Errorf("Error message should describe the TLS connection, got: %s", mt.fatalMsg)
+		}
+	}()
+
+	client := e2e.New(mt, e2e.Config{
+		BaseURL: server.URL,
+		TLS:     &e2e.TLSConfig{CAPEM: serverCAPEM(server)},
+	})
+	client.GET("/").
+		Execute(context.Background()).
+		ExpectTLS(e2e.TLSExpectation{SANs: []string{"api.example.com"}})
+}
@@ -0,0 +1,106 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/sivchari/e2e"
+	"github.com/sivchari/e2e/test/e2e/testserver"
+)
+
+func TestWebSocketEcho(t *testing.T) {
+	server := testserver.NewEchoServer()
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{BaseURL: server.URL})
+
+	conn := client.WS("/ws").
+		Authorization("Bearer token123").
+		Timeout(5 * time.Second).
+		Connect(t.Context())
+
+	if got := conn.Handshake().Header.Get("X-Echo-Authorization"); got != "Bearer token123" {
+		t.Errorf("Expected handshake to carry Authorization, got %q", got)
+	}
+
+	conn.SendText("hello").
+		ExpectText("hello").
+		SendText("order-42").
+		ExpectText(regexp.MustCompile(`^order-\d+$`)).
+		SendJSON(map[string]int{"count": 1}).
+		ExpectJSON(`{"count":1}`).
+		SendBinary([]byte{0x01, 0x02}).
+		ExpectBinary([]byte{0x01, 0x02}).
+		Close(websocket.CloseNormalClosure, "bye").
+		ExpectClose(websocket.CloseNormalClosure)
+}
+
+func TestWebSocketTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte("welcome"))
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "go away"))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+
+	defer server.Close()
+
+	client := e2e.New(t, e2e.Config{
+		BaseURL: server.URL,
+		TLS:     &e2e.TLSConfig{CAPEM: serverCAPEM(server)},
+	})
+
+	// Use the HTTP client first so the shared TLS config advertises HTTP/2.
+	client.GET("/").Execute(t.Context())
+
+	client.WS("/ws").
+		Connect(t.Context()).
+		ExpectText("welcome").
+		ExpectClose(websocket.ClosePolicyViolation)
+}
+
+func TestWebSocketErrorMessage(t *testing.T) {
+	server := testserver.NewEchoServer()
+	defer server.Close()
+
+	mt := &mockT{TB: t}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic from Fatal call")
+		}
+
+		if !strings.Contains(mt.fatalMsg, "=== WebSocket Failed ===") {
+			t.Errorf("Error message should contain header, got: %s", mt.fatalMsg)
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Sent:\n  #1 text: ping") {
+			t.Errorf("Error message should contain sent messages, got: %s", mt.fatalMsg)
+		}
+
+		if !strings.Contains(mt.fatalMsg, "Actual:   text: ping") {
+			t.Errorf("Error message should contain the received message, got: %s", mt.fatalMsg)
+		}
+	}()
+
+	client := e2e.New(mt, e2e.Config{BaseURL: server.URL})
+	client.WS("/ws").
+		Connect(context.Background()).
+		SendText("ping").
+		ExpectText("pong")
+}
@@ -0,0 +1,226 @@
+package e2e
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// TLSConfig configures TLS for the suite's transport.
+type TLSConfig struct {
+	// CAFile and CAPEM hold PEM encoded certificates trusted as roots.
+	// When either is set, the system roots are not used.
+	CAFile string
+	CAPEM  []byte
+	// CertFile/KeyFile or CertPEM/KeyPEM hold the client certificate for mutual TLS.
+	CertFile string
+	KeyFile  string
+	CertPEM  []byte
+	KeyPEM   []byte
+	// ServerName overrides the name used for SNI and certificate verification.
+	ServerName string
+	// MinVersion is the minimum TLS version, such as tls.VersionTLS12.
+	MinVersion uint16
+	// InsecureSkipVerify disables server certificate verification.
+	InsecureSkipVerify bool
+}
+
+// TLSExpectation describes the negotiated TLS connection. Zero fields are not checked.
+type TLSExpectation struct {
+	// Version is the negotiated TLS version, such as tls.VersionTLS13.
+	Version uint16
+	// CipherSuite is the negotiated cipher suite.
+	CipherSuite uint16
+	// NegotiatedProtocol is the ALPN protocol, such as "h2".
+	NegotiatedProtocol string
+	// Subject matches the peer certificate's common name or full subject.
+	Subject string
+	// SANs must all be present in the peer certificate's DNS names, IP addresses or URIs.
+	SANs []string
+}
+
+// clientConfig builds a *tls.Config from the settings.
+func (c *TLSConfig) clientConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         c.ServerName,
+		MinVersion:         c.MinVersion,
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec // Explicitly requested for self-signed test stacks.
+	}
+
+	roots, err := c.rootCAs()
+	if err != nil {
+		return nil, err
+	}
+
+	config.RootCAs = roots
+
+	cert, err := c.certificate()
+	if err != nil {
+		return nil, err
+	}
+
+	if cert != nil {
+		config.Certificates = []tls.Certificate{*cert}
+	}
+
+	return config, nil
+}
+
+// rootCAs loads the configured CA bundle, or returns nil to use the system roots.
+func (c *TLSConfig) rootCAs() (*x509.CertPool, error) {
+	if c.CAFile == "" && len(c.CAPEM) == 0 {
+		return nil, nil //nolint:nilnil // A nil pool selects the system roots.
+	}
+
+	pemData := c.CAPEM
+
+	if c.CAFile != "" {
+		data, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+
+		pemData = append(slices.Clone(pemData), data...)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemData) {
+		return nil, errors.New("no certificates found in CA bundle")
+	}
+
+	return pool, nil
+}
+
+// certificate loads the client certificate, or returns nil when none is configured.
+func (c *TLSConfig) certificate() (*tls.Certificate, error) {
+	switch {
+	case c.CertFile != "" || c.KeyFile != "":
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		return &cert, nil
+	case len(c.CertPEM) > 0 || len(c.KeyPEM) > 0:
+		cert, err := tls.X509KeyPair(c.CertPEM, c.KeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+
+		return &cert, nil
+	default:
+		return nil, nil //nolint:nilnil // No client certificate is configured.
+	}
+}
+
+// ExpectTLS validates the negotiated TLS connection state.
+func (h *HTTPBuilder) ExpectTLS(expected TLSExpectation) *HTTPBuilder {
+	return h.expect(func() error {
+		state := h.resp.TLS
+		if state == nil {
+			return errors.New(h.formatError("TLS mismatch", "TLS connection", "plaintext connection"))
+		}
+
+		if mismatch := tlsMismatch(state, expected); mismatch != "" {
+			return errors.New(h.formatError("TLS mismatch ("+mismatch+")", describeExpectedTLS(expected), describeTLS(state)))
+		}
+
+		return nil
+	})
+}
+
+// tlsMismatch returns the name of the first expectation state does not meet.
+func tlsMismatch(state *tls.ConnectionState, expected TLSExpectation) string {
+	switch {
+	case expected.Version != 0 && state.Version != expected.Version:
+		return "version"
+	case expected.CipherSuite != 0 && state.CipherSuite != expected.CipherSuite:
+		return "cipher suite"
+	case expected.NegotiatedProtocol != "" && state.NegotiatedProtocol != expected.NegotiatedProtocol:
+		return "negotiated protocol"
+	}
+
+	if expected.Subject == "" && len(expected.SANs) == 0 {
+		return ""
+	}
+
+	if len(state.PeerCertificates) == 0 {
+		return "peer certificate"
+	}
+
+	leaf := state.PeerCertificates[0]
+	if expected.Subject != "" && leaf.Subject.CommonName != expected.Subject && leaf.Subject.String() != expected.Subject {
+		return "subject"
+	}
+
+	sans := certificateSANs(leaf)
+	for _, san := range expected.SANs {
+		if !slices.Contains(sans, san) {
+			return "SAN"
+		}
+	}
+
+	return ""
+}
+
+// certificateSANs lists the subject alternative names of cert.
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := slices.Clone(cert.DNSNames)
+
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	return sans
+}
+
+// describeTLS summarizes a TLS connection state for error reports.
+func describeTLS(state *tls.ConnectionState) string {
+	parts := []string{tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite)}
+
+	if state.NegotiatedProtocol != "" {
+		parts = append(parts, "ALPN "+state.NegotiatedProtocol)
+	}
+
+	if len(state.PeerCertificates) > 0 {
+		leaf := state.PeerCertificates[0]
+		parts = append(parts, "subject "+leaf.Subject.String(), "SAN ["+strings.Join(certificateSANs(leaf), " ")+"]")
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// describeExpectedTLS summarizes a TLS expectation for error reports.
+func describeExpectedTLS(expected TLSExpectation) string {
+	var parts []string
+
+	if expected.Version != 0 {
+		parts = append(parts, tls.VersionName(expected.Version))
+	}
+
+	if expected.CipherSuite != 0 {
+		parts = append(parts, tls.CipherSuiteName(expected.CipherSuite))
+	}
+
+	if expected.NegotiatedProtocol != "" {
+		parts = append(parts, "ALPN "+expected.NegotiatedProtocol)
+	}
+
+	if expected.Subject != "" {
+		parts = append(parts, "subject "+expected.Subject)
+	}
+
+	if len(expected.SANs) > 0 {
+		parts = append(parts, "SAN ["+strings.Join(expected.SANs, " ")+"]")
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,140 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// newClient builds the suite's HTTP client from config.
+//
+// Timeouts are applied per request through the context, so a client built
+// here is left without one.
+func newClient(config Config) (*http.Client, error) {
+	if config.Handler != nil {
+		if config.Client != nil || config.Transport != nil {
+			return nil, errors.New("handler mode cannot be combined with Client or Transport")
+		}
+
+		return &http.Client{Transport: &handlerTransport{handler: config.Handler}}, nil
+	}
+
+	if config.Client != nil {
+		transport, err := customizeTransport(config.Client.Transport, config)
+		if err != nil {
+			return nil, err
+		}
+
+		client := *config.Client
+		client.Transport = transport
+
+		return &client, nil
+	}
+
+	transport, err := customizeTransport(config.Transport, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// customizeTransport applies the transport-level settings of config to rt.
+// rt is returned unchanged when no such settings are present; otherwise it
+// must be an *http.Transport, which is cloned before being modified.
+func customizeTransport(rt http.RoundTripper, config Config) (http.RoundTripper, error) {
+	if config.TLS == nil && config.Protocol == ProtocolAuto && config.Target == "" && config.DialContext == nil {
+		return rt, nil
+	}
+
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	base, ok := rt.(*http.Transport)
+	if !ok {
+		return nil, errors.New("TLS, protocol and dial settings require an *http.Transport")
+	}
+
+	protocols, err := config.Protocol.protocols()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := withProtocols(base, protocols)
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.clientConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	dial, err := dialer(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if dial != nil {
+		transport.DialContext = dial
+	}
+
+	return transport, nil
+}
+
+// dialFunc dials a network connection.
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// dialer returns the dial function configured by DialContext or Target, or
+// nil to keep the transport's own dialer.
+func dialer(config Config) (dialFunc, error) {
+	if config.DialContext != nil {
+		return config.DialContext, nil
+	}
+
+	if config.Target == "" {
+		return nil, nil //nolint:nilnil // The transport keeps its own dialer.
+	}
+
+	network, address, err := parseTarget(config.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	var d net.Dialer
+
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		conn, err := d.DialContext(ctx, network, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial target %s: %w", config.Target, err)
+		}
+
+		return conn, nil
+	}, nil
+}
+
+// parseTarget splits a dial target into its network and address.
+// Targets without a scheme are dialed over TCP.
+func parseTarget(target string) (string, string, error) {
+	scheme, address, found := strings.Cut(target, "://")
+	if !found {
+		return "tcp", target, nil
+	}
+
+	switch scheme {
+	case "unix", "tcp", "tcp4", "tcp6":
+	default:
+		return "", "", fmt.Errorf("unsupported target scheme %q", scheme)
+	}
+
+	if address == "" {
+		return "", "", fmt.Errorf("target %q has no address", target)
+	}
+
+	return scheme, address, nil
+}
@@ -0,0 +1,405 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WSBuilder builds a WebSocket connection.
+type WSBuilder struct {
+	suite   *TestSuite
+	path    string
+	headers map[string]string
+	query   map[string]string
+	timeout time.Duration
+}
+
+// WSConn is an open WebSocket connection with fluent send and expect helpers.
+type WSConn struct {
+	suite     *TestSuite
+	conn      *websocket.Conn
+	url       string
+	headers   http.Header
+	handshake *http.Response
+	timeout   time.Duration
+
+	// Message history for error reporting
+	sent     []wsMessage
+	received []wsMessage
+}
+
+// wsMessage is a sent or received frame kept for error reports.
+type wsMessage struct {
+	messageType int
+	data        []byte
+}
+
+// WS creates a WebSocket connection builder. The URL is resolved against
+// BaseURL with the scheme switched to ws or wss, and the handshake uses the
+// suite's dialer and TLS settings.
+func (s *TestSuite) WS(path string) *WSBuilder {
+	return &WSBuilder{
+		suite: s,
+		path:  path,
+	}
+}
+
+// Header sets a handshake request header.
+func (b *WSBuilder) Header(key, value string) *WSBuilder {
+	if b.headers == nil {
+		b.headers = make(map[string]string)
+	}
+
+	b.headers[key] = value
+
+	return b
+}
+
+// Query adds a query parameter to the handshake URL.
+func (b *WSBuilder) Query(key, value string) *WSBuilder {
+	if b.query == nil {
+		b.query = make(map[string]string)
+	}
+
+	b.query[key] = value
+
+	return b
+}
+
+// Authorization sets the handshake Authorization header.
+func (b *WSBuilder) Authorization(value string) *WSBuilder {
+	return b.Header("Authorization", value)
+}
+
+// Timeout sets how long the handshake and each expectation may take,
+// overriding Config.Timeout.
+func (b *WSBuilder) Timeout(timeout time.Duration) *WSBuilder {
+	b.timeout = timeout
+
+	return b
+}
+
+// Connect performs the WebSocket handshake.
+func (b *WSBuilder) Connect(ctx context.Context) *WSConn {
+	t := b.suite.t
+
+	wsURL, err := b.url()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	dialer, err := b.suite.wsDialer()
+	if err != nil {
+		t.Fatalf("Failed to configure WebSocket dialer: %v", err)
+	}
+
+	timeout := b.timeout
+	if timeout <= 0 {
+		timeout = b.suite.config.Timeout
+	}
+
+	dialer.HandshakeTimeout = timeout
+
+	headers := make(http.Header)
+	for key, value := range b.headers {
+		headers.Set(key, value)
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, wsURL, headers)
+	if err != nil {
+		t.Fatal(formatHandshakeError(wsURL, headers, resp, err))
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return &WSConn{
+		suite:     b.suite,
+		conn:      conn,
+		url:       wsURL,
+		headers:   headers,
+		handshake: resp,
+		timeout:   timeout,
+	}
+}
+
+// url resolves the handshake URL.
+func (b *WSBuilder) url() (string, error) {
+	wsURL, err := b.suite.resolveURL(b.path, b.query)
+	if err != nil {
+		return "", err
+	}
+
+	switch wsURL.Scheme {
+	case "http":
+		wsURL.Scheme = "ws"
+	case "https":
+		wsURL.Scheme = "wss"
+	}
+
+	return wsURL.String(), nil
+}
+
+// wsDialer builds a WebSocket dialer sharing the suite transport's dialer,
+// proxy and TLS settings.
+func (s *TestSuite) wsDialer() (*websocket.Dialer, error) {
+	transport := s.client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if _, ok := transport.(*handlerTransport); ok {
+		return nil, errors.New("WebSocket connections are not supported in handler mode")
+	}
+
+	dialer := &websocket.Dialer{Proxy: http.ProxyFromEnvironment}
+
+	if t, ok := transport.(*http.Transport); ok {
+		dialer.Proxy = t.Proxy
+		dialer.NetDialContext = t.DialContext
+
+		if t.TLSClientConfig != nil {
+			// The handshake is HTTP/1.1 only, so HTTP/2 must not be offered.
+			dialer.TLSClientConfig = t.TLSClientConfig.Clone()
+			dialer.TLSClientConfig.NextProtos = nil
+		}
+	}
+
+	return dialer, nil
+}
+
+// Handshake returns the handshake response.
+func (c *WSConn) Handshake() *http.Response {
+	return c.handshake
+}
+
+// Timeout sets how long each following expectation waits for a message.
+func (c *WSConn) Timeout(timeout time.Duration) *WSConn {
+	c.timeout = timeout
+
+	return c
+}
+
+// SendText sends a text message.
+func (c *WSConn) SendText(text string) *WSConn {
+	return c.send(websocket.TextMessage, []byte(text))
+}
+
+// SendBinary sends a binary message.
+func (c *WSConn) SendBinary(data []byte) *WSConn {
+	return c.send(websocket.BinaryMessage, data)
+}
+
+// SendJSON sends v encoded as JSON in a text message.
+func (c *WSConn) SendJSON(v interface{}) *WSConn {
+	data, err := json.Marshal(v)
+	if err != nil {
+		c.suite.t.Fatalf("Failed to marshal WebSocket message: %v", err)
+	}
+
+	return c.send(websocket.TextMessage, data)
+}
+
+// send writes a data frame.
+func (c *WSConn) send(messageType int, data []byte) *WSConn {
+	c.sent = append(c.sent, wsMessage{messageType: messageType, data: data})
+
+	if err := c.conn.SetWriteDeadline(time.Now().Add(c.timeout)); err != nil {
+		c.suite.t.Fatalf("Failed to set write deadline: %v", err)
+	}
+
+	if err := c.conn.WriteMessage(messageType, data); err != nil {
+		c.suite.t.Fatal(c.formatError("Send failed", "message sent", err.Error()))
+	}
+
+	return c
+}
+
+// ExpectText validates the next message is a text message matching
+// expected, as described by MatchText.
+func (c *WSConn) ExpectText(expected interface{}) *WSConn {
+	msg := c.next()
+	if msg.messageType != websocket.TextMessage || !MatchText(string(msg.data), expected) {
+		c.suite.t.Fatal(c.formatError("Message mismatch", "text: "+DescribeMatcher(expected), msg.String()))
+	}
+
+	return c
+}
+
+// ExpectJSON validates the next message is a text message whose JSON
+// equals expected, which may be a JSON string or any marshalable value.
+func (c *WSConn) ExpectJSON(expected interface{}) *WSConn {
+	normalized, err := normalizeJSON(expected)
+	if err != nil {
+		c.suite.t.Fatal(err.Error())
+	}
+
+	msg := c.next()
+
+	var actual interface{}
+	if msg.messageType != websocket.TextMessage || json.Unmarshal(msg.data, &actual) != nil || !jsonEqual(normalized, actual) {
+		expectedJSON, _ := json.Marshal(normalized)
+		c.suite.t.Fatal(c.formatError("JSON message mismatch", "text: "+string(expectedJSON), msg.String()))
+	}
+
+	return c
+}
+
+// ExpectBinary validates the next message is a binary message equal to expected.
+func (c *WSConn) ExpectBinary(expected []byte) *WSConn {
+	msg := c.next()
+	if msg.messageType != websocket.BinaryMessage || !bytes.Equal(msg.data, expected) {
+		expectedMsg := wsMessage{messageType: websocket.BinaryMessage, data: expected}
+		c.suite.t.Fatal(c.formatError("Message mismatch", expectedMsg.String(), msg.String()))
+	}
+
+	return c
+}
+
+// Close sends a close frame with code and reason. Follow with ExpectClose
+// to wait for the server's reply.
+func (c *WSConn) Close(code int, reason string) *WSConn {
+	deadline := time.Now().Add(c.timeout)
+
+	err := c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
+	if err != nil {
+		c.suite.t.Fatal(c.formatError("Close failed", "close frame sent", err.Error()))
+	}
+
+	return c
+}
+
+// ExpectClose reads until the server closes the connection and validates
+// the close code. Data messages received meanwhile are kept in the history.
+func (c *WSConn) ExpectClose(code int) *WSConn {
+	for {
+		msg, err := c.read()
+
+		var closeErr *websocket.CloseError
+		if errors.As(err, &closeErr) {
+			if closeErr.Code != code {
+				actual := fmt.Sprintf("%d %s", closeErr.Code, closeErr.Text)
+				c.suite.t.Fatal(c.formatError("Close code mismatch", fmt.Sprintf("%d", code), actual))
+			}
+
+			return c
+		}
+
+		if err != nil {
+			c.suite.t.Fatal(c.formatError("Close code mismatch", fmt.Sprintf("%d", code), err.Error()))
+		}
+
+		c.received = append(c.received, msg)
+	}
+}
+
+// next reads the next data message, failing the test on error or timeout.
+func (c *WSConn) next() wsMessage {
+	msg, err := c.read()
+	if err != nil {
+		c.suite.t.Fatal(c.formatError("Receive failed", "a message within "+c.timeout.String(), err.Error()))
+	}
+
+	c.received = append(c.received, msg)
+
+	return msg
+}
+
+// read reads one message within the expectation timeout.
+func (c *WSConn) read() (wsMessage, error) {
+	if err := c.conn.SetReadDeadline(time.Now().Add(c.timeout)); err != nil {
+		return wsMessage{}, fmt.Errorf("failed to set read deadline: %w", err)
+	}
+
+	messageType, data, err := c.conn.ReadMessage()
+	if err != nil {
+		return wsMessage{}, fmt.Errorf("failed to read message: %w", err)
+	}
+
+	return wsMessage{messageType: messageType, data: data}, nil
+}
+
+// String formats the message for error reports.
+func (m wsMessage) String() string {
+	switch m.messageType {
+	case websocket.TextMessage:
+		return "text: " + truncateString(string(m.data))
+	case websocket.BinaryMessage:
+		if len(m.data) > maxBodySize {
+			return fmt.Sprintf("binary: %d bytes %x... (truncated)", len(m.data), m.data[:maxBodySize])
+		}
+
+		return fmt.Sprintf("binary: %d bytes %x", len(m.data), m.data)
+	default:
+		return "(none)"
+	}
+}
+
+// formatError creates a detailed error message with connection and message history.
+func (c *WSConn) formatError(assertion, expected, actual string) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n=== WebSocket Failed ===\n")
+	fmt.Fprintf(&sb, "URL:      %s\n", c.url)
+
+	writeHeaderLines(&sb, c.headers)
+
+	fmt.Fprintf(&sb, "Handshake: %s\n", c.handshake.Status)
+	writeMessages(&sb, "Sent", c.sent)
+	writeMessages(&sb, "Received", c.received)
+
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "%s:\n", assertion)
+	fmt.Fprintf(&sb, "Expected: %s\n", expected)
+	fmt.Fprintf(&sb, "Actual:   %s\n", actual)
+
+	return sb.String()
+}
+
+// formatHandshakeError describes a failed WebSocket handshake.
+func formatHandshakeError(wsURL string, headers http.Header, resp *http.Response, err error) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n=== WebSocket Failed ===\n")
+	fmt.Fprintf(&sb, "URL:      %s\n", wsURL)
+
+	writeHeaderLines(&sb, headers)
+
+	if resp != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		_ = resp.Body.Close()
+
+		fmt.Fprintf(&sb, "\nResponse: %s\n", resp.Status)
+
+		if len(body) > 0 {
+			fmt.Fprintf(&sb, "Body:     %s\n", body)
+		}
+	}
+
+	fmt.Fprintf(&sb, "\nHandshake failed:\n%v\n", err)
+
+	return sb.String()
+}
+
+// writeMessages writes a message history section.
+func writeMessages(sb *strings.Builder, label string, messages []wsMessage) {
+	if len(messages) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "%s:\n", label)
+
+	for i, msg := range messages {
+		fmt.Fprintf(sb, "  #%d %s\n", i+1, msg)
+	}
+}